@@ -9,8 +9,10 @@ require (
 	github.com/emicklei/go-restful/v3 v3.4.0
 	github.com/go-openapi/runtime v0.19.26
 	github.com/go-openapi/spec v0.20.2
+	github.com/spf13/pflag v1.0.5
 	github.com/stretchr/testify v1.7.0 // indirect
 	golang.org/x/crypto v0.0.0-20201124201722-c8d3bf9c5392 // indirect
+	golang.org/x/net v0.0.0-20210119194325-5f4716e94777
 	golang.org/x/oauth2 v0.0.0-20201109201403-9fd604954f58 // indirect
 	golang.org/x/sys v0.0.0-20210124154548-22da62e12c0c // indirect
 	google.golang.org/appengine v1.6.7 // indirect
@@ -19,4 +21,5 @@ require (
 	k8s.io/apiextensions-apiserver v0.20.2
 	k8s.io/apimachinery v0.20.2
 	k8s.io/client-go v0.20.2
+	sigs.k8s.io/yaml v1.2.0
 )
@@ -1,5 +1,7 @@
 package args
 
+import "time"
+
 var builder = &holderBuilder{holder: Holder}
 
 type holderBuilder struct {
@@ -11,6 +13,46 @@ func (self *holderBuilder) SetPort(port int) *holderBuilder {
 	return self
 }
 
+func (self *holderBuilder) SetSecurePort(securePort int) *holderBuilder {
+	self.holder.securePort = securePort
+	return self
+}
+
+func (self *holderBuilder) SetTlsCertFile(tlsCertFile string) *holderBuilder {
+	self.holder.tlsCertFile = tlsCertFile
+	return self
+}
+
+func (self *holderBuilder) SetTlsKeyFile(tlsKeyFile string) *holderBuilder {
+	self.holder.tlsKeyFile = tlsKeyFile
+	return self
+}
+
+func (self *holderBuilder) SetAutoGenerateCertificates(autoGenerateCertificates bool) *holderBuilder {
+	self.holder.autoGenerateCertificates = autoGenerateCertificates
+	return self
+}
+
+func (self *holderBuilder) SetRedirectToHttps(redirectToHttps bool) *holderBuilder {
+	self.holder.redirectToHttps = redirectToHttps
+	return self
+}
+
+func (self *holderBuilder) SetTlsClientCaFile(tlsClientCaFile string) *holderBuilder {
+	self.holder.tlsClientCaFile = tlsClientCaFile
+	return self
+}
+
+func (self *holderBuilder) SetTlsRequireClientCert(tlsRequireClientCert bool) *holderBuilder {
+	self.holder.tlsRequireClientCert = tlsRequireClientCert
+	return self
+}
+
+func (self *holderBuilder) SetTlsClientCertGroups(tlsClientCertGroups []string) *holderBuilder {
+	self.holder.tlsClientCertGroups = tlsClientCertGroups
+	return self
+}
+
 func (self *holderBuilder) SetApiServerHost(apiServerHost string) *holderBuilder {
 	self.holder.apiServerHost = apiServerHost
 	return self
@@ -21,6 +63,16 @@ func (self *holderBuilder) SetKubeConfigFile(kubeConfigFile string) *holderBuild
 	return self
 }
 
+func (self *holderBuilder) SetKubeConfigContexts(kubeConfigContexts []string) *holderBuilder {
+	self.holder.kubeConfigContexts = kubeConfigContexts
+	return self
+}
+
+func (self *holderBuilder) SetKubeConfigDir(kubeConfigDir string) *holderBuilder {
+	self.holder.kubeConfigDir = kubeConfigDir
+	return self
+}
+
 func (self *holderBuilder) SetApiLogLevel(apiLogLevel string) *holderBuilder {
 	self.holder.apiLogLevel = apiLogLevel
 	return self
@@ -31,6 +83,91 @@ func (self *holderBuilder) SetNamespace(namespace string) *holderBuilder {
 	return self
 }
 
+func (self *holderBuilder) SetEnableSkipLogin(enableSkipLogin bool) *holderBuilder {
+	self.holder.enableSkipLogin = enableSkipLogin
+	return self
+}
+
+func (self *holderBuilder) SetProtectedResources(protectedResources []string) *holderBuilder {
+	self.holder.protectedResources = protectedResources
+	return self
+}
+
+func (self *holderBuilder) SetOidcIssuerUrl(oidcIssuerUrl string) *holderBuilder {
+	self.holder.oidcIssuerUrl = oidcIssuerUrl
+	return self
+}
+
+func (self *holderBuilder) SetOidcClientId(oidcClientId string) *holderBuilder {
+	self.holder.oidcClientId = oidcClientId
+	return self
+}
+
+func (self *holderBuilder) SetOidcJwksFile(oidcJwksFile string) *holderBuilder {
+	self.holder.oidcJwksFile = oidcJwksFile
+	return self
+}
+
+func (self *holderBuilder) SetOidcJwksUrl(oidcJwksUrl string) *holderBuilder {
+	self.holder.oidcJwksUrl = oidcJwksUrl
+	return self
+}
+
+func (self *holderBuilder) SetOidcUsernameClaim(oidcUsernameClaim string) *holderBuilder {
+	self.holder.oidcUsernameClaim = oidcUsernameClaim
+	return self
+}
+
+func (self *holderBuilder) SetOidcUsernamePrefix(oidcUsernamePrefix string) *holderBuilder {
+	self.holder.oidcUsernamePrefix = oidcUsernamePrefix
+	return self
+}
+
+func (self *holderBuilder) SetOidcGroupsClaim(oidcGroupsClaim string) *holderBuilder {
+	self.holder.oidcGroupsClaim = oidcGroupsClaim
+	return self
+}
+
+func (self *holderBuilder) SetOidcGroupsPrefix(oidcGroupsPrefix string) *holderBuilder {
+	self.holder.oidcGroupsPrefix = oidcGroupsPrefix
+	return self
+}
+
+func (self *holderBuilder) SetOidcImpersonate(oidcImpersonate bool) *holderBuilder {
+	self.holder.oidcImpersonate = oidcImpersonate
+	return self
+}
+
+func (self *holderBuilder) SetLoginMaxAttemptsPerUser(loginMaxAttemptsPerUser int) *holderBuilder {
+	self.holder.loginMaxAttemptsPerUser = loginMaxAttemptsPerUser
+	return self
+}
+
+func (self *holderBuilder) SetLoginMaxAttemptsPerIp(loginMaxAttemptsPerIp int) *holderBuilder {
+	self.holder.loginMaxAttemptsPerIp = loginMaxAttemptsPerIp
+	return self
+}
+
+func (self *holderBuilder) SetEncryptionKeyRotationInterval(interval time.Duration) *holderBuilder {
+	self.holder.encryptionKeyRotationInterval = interval
+	return self
+}
+
+func (self *holderBuilder) SetLoginBackoff(loginBackoff time.Duration) *holderBuilder {
+	self.holder.loginBackoff = loginBackoff
+	return self
+}
+
+func (self *holderBuilder) SetLoginLockout(loginLockout time.Duration) *holderBuilder {
+	self.holder.loginLockout = loginLockout
+	return self
+}
+
+func (self *holderBuilder) SetLogStreamMaxLinesPerSecond(logStreamMaxLinesPerSecond int) *holderBuilder {
+	self.holder.logStreamMaxLinesPerSecond = logStreamMaxLinesPerSecond
+	return self
+}
+
 func GetHolderBuilder() *holderBuilder {
 	return builder
 }
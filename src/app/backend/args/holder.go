@@ -1,19 +1,85 @@
 package args
 
+import "time"
+
 var Holder = &holder{}
 
 type holder struct {
-	port           int
-	apiServerHost  string
-	kubeConfigFile string
-	apiLogLevel    string
-	namespace      string
+	port               int
+	apiServerHost      string
+	kubeConfigFile     string
+	kubeConfigContexts []string
+	kubeConfigDir      string
+	apiLogLevel        string
+	namespace          string
+
+	securePort               int
+	tlsCertFile              string
+	tlsKeyFile               string
+	autoGenerateCertificates bool
+	redirectToHttps          bool
+	tlsClientCaFile          string
+	tlsRequireClientCert     bool
+	tlsClientCertGroups      []string
+
+	enableSkipLogin               bool
+	protectedResources            []string
+	encryptionKeyRotationInterval time.Duration
+
+	loginMaxAttemptsPerUser int
+	loginMaxAttemptsPerIp   int
+	loginBackoff            time.Duration
+	loginLockout            time.Duration
+
+	logStreamMaxLinesPerSecond int
+
+	oidcIssuerUrl      string
+	oidcClientId       string
+	oidcJwksFile       string
+	oidcJwksUrl        string
+	oidcUsernameClaim  string
+	oidcUsernamePrefix string
+	oidcGroupsClaim    string
+	oidcGroupsPrefix   string
+	oidcImpersonate    bool
 }
 
 func (self *holder) GetPort() int {
 	return self.port
 }
 
+func (self *holder) GetSecurePort() int {
+	return self.securePort
+}
+
+func (self *holder) GetTlsCertFile() string {
+	return self.tlsCertFile
+}
+
+func (self *holder) GetTlsKeyFile() string {
+	return self.tlsKeyFile
+}
+
+func (self *holder) GetAutoGenerateCertificates() bool {
+	return self.autoGenerateCertificates
+}
+
+func (self *holder) GetRedirectToHttps() bool {
+	return self.redirectToHttps
+}
+
+func (self *holder) GetTlsClientCaFile() string {
+	return self.tlsClientCaFile
+}
+
+func (self *holder) GetTlsRequireClientCert() bool {
+	return self.tlsRequireClientCert
+}
+
+func (self *holder) GetTlsClientCertGroups() []string {
+	return self.tlsClientCertGroups
+}
+
 func (self *holder) GetApiServerHost() string {
 	return self.apiServerHost
 }
@@ -22,6 +88,14 @@ func (self *holder) GetKubeConfigFile() string {
 	return self.kubeConfigFile
 }
 
+func (self *holder) GetKubeConfigContexts() []string {
+	return self.kubeConfigContexts
+}
+
+func (self *holder) GetKubeConfigDir() string {
+	return self.kubeConfigDir
+}
+
 func (self *holder) GetApiLogLevel() string {
 	return self.apiLogLevel
 }
@@ -29,3 +103,71 @@ func (self *holder) GetApiLogLevel() string {
 func (self *holder) GetNamespace() string {
 	return self.namespace
 }
+
+func (self *holder) GetEnableSkipLogin() bool {
+	return self.enableSkipLogin
+}
+
+func (self *holder) GetProtectedResources() []string {
+	return self.protectedResources
+}
+
+func (self *holder) GetOidcIssuerUrl() string {
+	return self.oidcIssuerUrl
+}
+
+func (self *holder) GetOidcClientId() string {
+	return self.oidcClientId
+}
+
+func (self *holder) GetOidcJwksFile() string {
+	return self.oidcJwksFile
+}
+
+func (self *holder) GetOidcJwksUrl() string {
+	return self.oidcJwksUrl
+}
+
+func (self *holder) GetOidcUsernameClaim() string {
+	return self.oidcUsernameClaim
+}
+
+func (self *holder) GetOidcUsernamePrefix() string {
+	return self.oidcUsernamePrefix
+}
+
+func (self *holder) GetOidcGroupsClaim() string {
+	return self.oidcGroupsClaim
+}
+
+func (self *holder) GetOidcGroupsPrefix() string {
+	return self.oidcGroupsPrefix
+}
+
+func (self *holder) GetOidcImpersonate() bool {
+	return self.oidcImpersonate
+}
+
+func (self *holder) GetEncryptionKeyRotationInterval() time.Duration {
+	return self.encryptionKeyRotationInterval
+}
+
+func (self *holder) GetLoginMaxAttemptsPerUser() int {
+	return self.loginMaxAttemptsPerUser
+}
+
+func (self *holder) GetLoginMaxAttemptsPerIp() int {
+	return self.loginMaxAttemptsPerIp
+}
+
+func (self *holder) GetLoginBackoff() time.Duration {
+	return self.loginBackoff
+}
+
+func (self *holder) GetLoginLockout() time.Duration {
+	return self.loginLockout
+}
+
+func (self *holder) GetLogStreamMaxLinesPerSecond() int {
+	return self.logStreamMaxLinesPerSecond
+}
@@ -0,0 +1,104 @@
+package args
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+)
+
+// EnvPrefix is the prefix of environment variables that can be used instead of command line flags,
+// e.g. KUBEPORTAL_API_LOG_LEVEL for --api-log-level.
+const EnvPrefix = "KUBEPORTAL_"
+
+// Parse parses command line arguments into the flag set. Flags that are not given on the command line are read
+// from KUBEPORTAL_* environment variables and then from the YAML config file named by the configFlag flag.
+// Keys of the config file are flag names, e.g. `api-log-level: DEBUG`.
+func Parse(flagSet *pflag.FlagSet, arguments []string, configFlag string) error {
+	if err := flagSet.Parse(arguments); err != nil {
+		return err
+	}
+
+	if err := setFromEnv(flagSet); err != nil {
+		return err
+	}
+
+	configFile, err := flagSet.GetString(configFlag)
+	if err != nil || len(configFile) == 0 {
+		return err
+	}
+
+	return setFromConfigFile(flagSet, configFile)
+}
+
+// ToEnvName returns name of the environment variable of the flag, e.g. KUBEPORTAL_API_LOG_LEVEL.
+func ToEnvName(flagName string) string {
+	return EnvPrefix + strings.ToUpper(strings.Replace(flagName, "-", "_", -1))
+}
+
+func setFromEnv(flagSet *pflag.FlagSet) error {
+	var err error
+	flagSet.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || flag.Changed {
+			return
+		}
+
+		if value, exists := os.LookupEnv(ToEnvName(flag.Name)); exists {
+			if setErr := flagSet.Set(flag.Name, value); setErr != nil {
+				err = fmt.Errorf("invalid value of %s environment variable: %s", ToEnvName(flag.Name), setErr)
+			}
+		}
+	})
+
+	return err
+}
+
+func setFromConfigFile(flagSet *pflag.FlagSet, configFile string) error {
+	data, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return err
+	}
+
+	config := map[string]interface{}{}
+	if err = yaml.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("could not parse config file %s: %s", configFile, err)
+	}
+
+	for name, value := range config {
+		flag := flagSet.Lookup(name)
+		if flag == nil {
+			return fmt.Errorf("unknown option %s in config file %s", name, configFile)
+		}
+
+		if flag.Changed {
+			continue
+		}
+
+		values, isList := value.([]interface{})
+		if !isList {
+			values = []interface{}{value}
+		}
+
+		// Every item is set separately, so that list flags get all of them.
+		for _, item := range values {
+			if err = flagSet.Set(name, toFlagValue(item)); err != nil {
+				return fmt.Errorf("invalid value of %s in config file %s: %s", name, configFile, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func toFlagValue(value interface{}) string {
+	// Numbers are decoded as float64, format them without an exponent so that integer flags can parse them.
+	if number, ok := value.(float64); ok {
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+
+	return fmt.Sprint(value)
+}
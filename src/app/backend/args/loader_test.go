@@ -0,0 +1,90 @@
+package args_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/pflag"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/args"
+)
+
+func TestParse(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	configFile := filepath.Join(dir, "config.yaml")
+	config := "port: 8443\nnamespace: from-file\napi-log-level: DEBUG\nprotected-resource:\n- secret/a\n- secret/b\n"
+	if err = ioutil.WriteFile(configFile, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		arguments         []string
+		env               map[string]string
+		expectedPort      int
+		expectedNamespace string
+		expectedLogLevel  string
+		expectedResources []string
+		expectedErr       bool
+	}{
+		{nil, nil, 9090, "default", "INFO", []string{}, false},
+		{[]string{"--config", configFile}, nil, 8443, "from-file", "DEBUG", []string{"secret/a", "secret/b"}, false},
+		{
+			[]string{"--namespace", "from-flag"},
+			map[string]string{"KUBEPORTAL_NAMESPACE": "from-env", "KUBEPORTAL_CONFIG": configFile},
+			8443, "from-flag", "DEBUG", []string{"secret/a", "secret/b"}, false,
+		},
+		{
+			[]string{"--config", configFile},
+			map[string]string{"KUBEPORTAL_API_LOG_LEVEL": "NONE", "KUBEPORTAL_PROTECTED_RESOURCE": "secret/c"},
+			8443, "from-file", "NONE", []string{"secret/c"}, false,
+		},
+		{nil, map[string]string{"KUBEPORTAL_PORT": "invalid"}, 0, "", "", nil, true},
+	}
+
+	for _, c := range cases {
+		for name, value := range c.env {
+			os.Setenv(name, value)
+		}
+
+		flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flagSet.String("config", "", "")
+		port := flagSet.Int("port", 9090, "")
+		namespace := flagSet.String("namespace", "default", "")
+		logLevel := flagSet.String("api-log-level", "INFO", "")
+		resources := flagSet.StringSlice("protected-resource", []string{}, "")
+		err := args.Parse(flagSet, c.arguments, "config")
+
+		for name := range c.env {
+			os.Unsetenv(name)
+		}
+
+		if (err != nil) != c.expectedErr {
+			t.Fatalf("Parse(%v, %v): Expected error to be %v but got: %v", c.arguments, c.env, c.expectedErr, err)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if *port != c.expectedPort || *namespace != c.expectedNamespace || *logLevel != c.expectedLogLevel ||
+			!reflect.DeepEqual(*resources, c.expectedResources) {
+			t.Errorf("Parse(%v, %v): Expected %d, %s, %s, %v but got %d, %s, %s, %v", c.arguments, c.env,
+				c.expectedPort, c.expectedNamespace, c.expectedLogLevel, c.expectedResources, *port, *namespace,
+				*logLevel, *resources)
+		}
+	}
+}
+
+func TestToEnvName(t *testing.T) {
+	if actual := args.ToEnvName("oidc-issuer-url"); actual != "KUBEPORTAL_OIDC_ISSUER_URL" {
+		t.Errorf("ToEnvName(oidc-issuer-url): Expected KUBEPORTAL_OIDC_ISSUER_URL but got %s", actual)
+	}
+}
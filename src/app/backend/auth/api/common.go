@@ -1,8 +1,10 @@
 package api
 
 import (
+	"log"
 	"strings"
 
+	backendApi "github.com/donghoon-khan/kubeportal/src/app/backend/api"
 	"github.com/donghoon-khan/kubeportal/src/app/backend/args"
 )
 
@@ -10,7 +12,7 @@ func ToAuthenticationModes(modes []string) AuthenticationModes {
 	result := AuthenticationModes{}
 	modesMap := map[string]bool{}
 
-	for _, mode := range []AuthenticationMode{Token, Basic} {
+	for _, mode := range []AuthenticationMode{Token, Basic, OIDC, ClientCertificate} {
 		modesMap[mode.String()] = true
 	}
 
@@ -23,16 +25,38 @@ func ToAuthenticationModes(modes []string) AuthenticationModes {
 	return result
 }
 
-func ShouldRejectRequest(url string) bool {
-	for _, protectedResource := range protectedResources {
-		if strings.Contains(url, protectedResource.ResourceName) && strings.Contains(url, protectedResource.ResourceNamespace) {
-			return true
-		}
+// ProtectedResources returns secrets holding keys of the portal together with resources configured with
+// args.Holder.GetProtectedResources. The list is built on every call, because namespace of the portal is not
+// known during package initialization.
+func ProtectedResources() []ProtectedResource {
+	namespace := args.Holder.GetNamespace()
+	result := []ProtectedResource{
+		{backendApi.ResourceKindSecret, EncryptionKeyHolderName, namespace},
+		{backendApi.ResourceKindSecret, CertificateHolderSecretName, namespace},
+		{backendApi.ResourceKindSecret, RevocationHolderSecretName, namespace},
+		{backendApi.ResourceKindSecret, AccessTokenHolderSecretName, namespace},
+		{backendApi.ResourceKindSecret, CsrfTokenSecretName, namespace},
 	}
-	return false
+
+	return append(result, ToProtectedResources(args.Holder.GetProtectedResources())...)
 }
 
-var protectedResources = []ProtectedResource{
-	{EncryptionKeyHolderName, args.Holder.GetNamespace()},
-	{CertificateHolderSecretName, args.Holder.GetNamespace()},
+// ToProtectedResources parses resources given in kind/namespace/name or kind/name format. The latter matches
+// resources in every namespace. Invalid entries are skipped.
+func ToProtectedResources(resources []string) []ProtectedResource {
+	result := make([]ProtectedResource, 0, len(resources))
+	for _, resource := range resources {
+		parts := strings.Split(resource, "/")
+		switch {
+		case len(parts) == 2 && len(parts[0]) > 0 && len(parts[1]) > 0:
+			result = append(result, ProtectedResource{ResourceKind: parts[0], ResourceName: parts[1]})
+		case len(parts) == 3 && len(parts[0]) > 0 && len(parts[2]) > 0:
+			result = append(result, ProtectedResource{parts[0], parts[2], parts[1]})
+		default:
+			log.Printf("Skipping invalid protected resource %q, expected kind/namespace/name or kind/name",
+				resource)
+		}
+	}
+
+	return result
 }
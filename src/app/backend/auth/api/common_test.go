@@ -3,6 +3,8 @@ package api
 import (
 	"reflect"
 	"testing"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/args"
 )
 
 func TestToAuthenticationModes(t *testing.T) {
@@ -13,6 +15,8 @@ func TestToAuthenticationModes(t *testing.T) {
 		{[]string{}, AuthenticationModes{}},
 		{[]string{"token"}, AuthenticationModes{Token: true}},
 		{[]string{"token", "basic", "test"}, AuthenticationModes{Token: true, Basic: true}},
+		{[]string{"oidc"}, AuthenticationModes{OIDC: true}},
+		{[]string{"clientcertificate"}, AuthenticationModes{ClientCertificate: true}},
 	}
 
 	for _, c := range cases {
@@ -23,26 +27,78 @@ func TestToAuthenticationModes(t *testing.T) {
 	}
 }
 
-func TestShouldRejectRequest(t *testing.T) {
+func TestProtectedResources(t *testing.T) {
+	cases := []struct {
+		kind, namespace, name string
+		expected              bool
+	}{
+		{"secret", "kube-system", "test-secret", false},
+		{"secret", "kube-system", "kubernetes-dashboard-key-holder", true},
+		{"secret", "test", "kubernetes-dashboard-certs", false},
+		{"secret", "kube-system", "kubernetes-dashboard-certs", true},
+		{"secret", "kube-system", "kube-portal-csrf", true},
+		{"namespace", "", "kube-system", false},
+		{"configmap", "default", "test-config", true},
+	}
+
+	args.GetHolderBuilder().SetNamespace("kube-system").SetProtectedResources([]string{"configmap/test-config"})
+	defer func() {
+		args.GetHolderBuilder().SetNamespace("").SetProtectedResources(nil)
+	}()
+
+	for _, c := range cases {
+		got := false
+		for _, resource := range ProtectedResources() {
+			got = got || resource.Matches(c.kind, c.namespace, c.name)
+		}
+
+		if got != c.expected {
+			t.Errorf("ProtectedResources(): expected %s/%s/%s to be protected %v, but got %v", c.kind, c.namespace,
+				c.name, c.expected, got)
+		}
+	}
+}
+
+func TestToProtectedResources(t *testing.T) {
 	cases := []struct {
-		url      string
-		expected bool
+		resources []string
+		expected  []ProtectedResource
 	}{
-		{"#!/namespace?namespace=test", false},
-		{"#!/namespace/test", false},
-		{"#!/namespace?namespace=kube-system", false},
-		{"#!/namespace/kube-system", false},
-		{"#!/secret/test/test-secret?namespace=test", false},
-		{"#!/secret/kube-system/test-secret", false},
-		{"#!/secret/kube-system/kubernetes-dashboard-key-holder", true},
-		{"#!/secret/test/kubernetes-dashboard-certs", true},
-		{"#!/secret/kube-system/kubernetes-dashboard-certs", true},
+		{[]string{}, []ProtectedResource{}},
+		{
+			[]string{"secret/kube-system/test-secret", "configmap/test-config"},
+			[]ProtectedResource{
+				{ResourceKind: "secret", ResourceName: "test-secret", ResourceNamespace: "kube-system"},
+				{ResourceKind: "configmap", ResourceName: "test-config"},
+			},
+		},
+		{[]string{"secret", "secret/kube-system/", "/test-secret"}, []ProtectedResource{}},
 	}
 
 	for _, c := range cases {
-		got := ShouldRejectRequest(c.url)
+		got := ToProtectedResources(c.resources)
 		if !reflect.DeepEqual(got, c.expected) {
-			t.Fatalf("ShouldRejectRequest(): url %s expected %v, but got %v", c.url, c.expected, got)
+			t.Fatalf("ToProtectedResources(%v): expected %v, but got %v", c.resources, c.expected, got)
+		}
+	}
+}
+
+func TestProtectedResourceMatches(t *testing.T) {
+	cases := []struct {
+		resource              ProtectedResource
+		kind, namespace, name string
+		expected              bool
+	}{
+		{ProtectedResource{"secret", "test-secret", "kube-system"}, "secret", "kube-system", "test-secret", true},
+		{ProtectedResource{"secret", "test-secret", "kube-system"}, "Secret", "kube-system", "test-secret", true},
+		{ProtectedResource{"secret", "test-secret", "kube-system"}, "secret", "default", "test-secret", false},
+		{ProtectedResource{"secret", "test-secret", "kube-system"}, "configmap", "kube-system", "test-secret", false},
+		{ProtectedResource{"secret", "test-secret", ""}, "secret", "default", "test-secret", true},
+	}
+
+	for _, c := range cases {
+		if got := c.resource.Matches(c.kind, c.namespace, c.name); got != c.expected {
+			t.Errorf("Matches(%s, %s, %s): expected %v, but got %v", c.kind, c.namespace, c.name, c.expected, got)
 		}
 	}
 }
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"crypto/x509"
+	"strings"
 	"time"
 
 	"k8s.io/client-go/tools/clientcmd/api"
@@ -9,16 +11,31 @@ import (
 const (
 	EncryptionKeyHolderName     = "kubernetes-dashboard-key-holder"
 	CertificateHolderSecretName = "kubernetes-dashboard-certs"
+	RevocationHolderSecretName  = "kubernetes-dashboard-revocations"
+	AccessTokenHolderSecretName = "kubernetes-dashboard-access-tokens"
+	CsrfTokenSecretName         = "kube-portal-csrf"
 	DefaultTokenTTL             = 900
+
+	// LoginSucceededAttribute is set on login requests that issued a token, so that filters can tell failed
+	// logins apart.
+	LoginSucceededAttribute = "loginSucceeded"
 )
 
 type AuthenticationModes map[AuthenticationMode]bool
 
+// ProtectedResource describes resource that can not be read or modified through the portal API. Empty
+// ResourceNamespace matches every namespace.
 type ProtectedResource struct {
+	ResourceKind      string
 	ResourceName      string
 	ResourceNamespace string
 }
 
+func (self ProtectedResource) Matches(kind, namespace, name string) bool {
+	return strings.EqualFold(self.ResourceKind, kind) && self.ResourceName == name &&
+		(len(self.ResourceNamespace) == 0 || self.ResourceNamespace == namespace)
+}
+
 func (self AuthenticationModes) IsEnabled(mode AuthenticationMode) bool {
 	_, exists := self[mode]
 	return exists
@@ -46,19 +63,38 @@ func (self AuthenticationMode) String() string {
 const (
 	Token AuthenticationMode = "token"
 	Basic AuthenticationMode = "basic"
+	OIDC  AuthenticationMode = "oidc"
+	// ClientCertificate authenticates users by X.509 client certificates presented during the TLS handshake.
+	ClientCertificate AuthenticationMode = "clientcertificate"
 )
 
 type AuthManager interface {
 	Login(*LoginSpec) (*AuthResponse, error)
 	Refresh(string) (string, error)
+	// Logout revokes the token, so that it can not be used anymore even though it has not expired yet.
+	Logout(string) error
+	// RevokeUser revokes all tokens issued to the user so far, including personal access tokens.
+	RevokeUser(string) error
 	AuthenticationModes() []AuthenticationMode
 	AuthenticationSkippable() bool
 }
 
 type TokenManager interface {
-	Generate(api.AuthInfo) (string, error)
-	Decrypt(string) (*api.AuthInfo, error)
+	// Generate encrypts AuthInfo into a token issued to the subject for the cluster. Subject can be empty when the
+	// user is not known, such token can only be revoked on its own. Empty cluster stands for the default cluster.
+	Generate(authInfo api.AuthInfo, subject, cluster string) (string, error)
+	// Decrypt returns AuthInfo of the token. Token issued for other cluster than the given one is rejected.
+	Decrypt(jweToken, cluster string) (*api.AuthInfo, error)
+	// GenerateURLToken encrypts AuthInfo into a token that expires after the TTL and can be passed only in the
+	// query of the given URL path, e.g. by browser clients that can not set headers.
+	GenerateURLToken(authInfo api.AuthInfo, path string, ttl time.Duration) (string, error)
+	// DecryptURLToken returns AuthInfo of the URL token generated for the path.
+	DecryptURLToken(jweToken, path string) (*api.AuthInfo, error)
 	Refresh(string) (string, error)
+	// Revoke makes the token invalid before it expires.
+	Revoke(string) error
+	// RevokeSubject makes all tokens issued to the subject so far invalid.
+	RevokeSubject(string) error
 	SetTokenTTL(time.Duration)
 }
 
@@ -67,11 +103,89 @@ type Authenticator interface {
 	GetAuthInfo() (api.AuthInfo, error)
 }
 
+// IDTokenVerifier validates OpenID Connect ID tokens offline against the issuer's key set.
+type IDTokenVerifier interface {
+	// Verify checks signature, issuer, audience and expiry of the ID token and returns identity it describes.
+	Verify(rawIDToken string) (*IDTokenClaims, error)
+	// Impersonate returns true when verified identity should be passed to the cluster with impersonation
+	// instead of using the ID token as a bearer token.
+	Impersonate() bool
+}
+
+type IDTokenClaims struct {
+	Username string   `json:"username"`
+	Groups   []string `json:"groups"`
+}
+
+// Identity is the user and groups credentials authenticate as.
+type Identity struct {
+	Username string   `json:"username"`
+	Groups   []string `json:"groups"`
+}
+
+// PersonalAccessTokenPrefix starts every personal access token, so that it can be told apart from tokens of the
+// cluster.
+const PersonalAccessTokenPrefix = "kpat_"
+
+type PersonalAccessTokenScope string
+
+const (
+	// ReadScope allows only requests that do not modify anything.
+	ReadScope PersonalAccessTokenScope = "read"
+	// WriteScope allows all requests.
+	WriteScope PersonalAccessTokenScope = "write"
+)
+
+// PersonalAccessTokenManager issues long lived tokens that act as the identity of their owner, e.g. for scripts.
+type PersonalAccessTokenManager interface {
+	// Create issues a new token to the owner. Returned token is the only place the token can be read from.
+	Create(owner Identity, spec PersonalAccessTokenSpec) (*PersonalAccessTokenResponse, error)
+	// List returns tokens of the owner, without the tokens themselves.
+	List(owner string) (*PersonalAccessTokenList, error)
+	// Revoke deletes token of the owner.
+	Revoke(owner, id string) error
+	// RevokeOwner deletes all tokens of the owner.
+	RevokeOwner(owner string) error
+	// Resolve returns auth info of the token if it is valid and its scopes allow the HTTP method. Groups of the
+	// owner are the ones the owner had when the token was created.
+	Resolve(token, method string) (*api.AuthInfo, error)
+}
+
+type PersonalAccessTokenSpec struct {
+	Name              string                     `json:"name"`
+	Scopes            []PersonalAccessTokenScope `json:"scopes"`
+	ExpirationSeconds int64                      `json:"expirationSeconds"`
+}
+
+type PersonalAccessToken struct {
+	ID        string                     `json:"id"`
+	Name      string                     `json:"name"`
+	Scopes    []PersonalAccessTokenScope `json:"scopes"`
+	CreatedAt time.Time                  `json:"createdAt"`
+	ExpiresAt time.Time                  `json:"expiresAt"`
+}
+
+type PersonalAccessTokenList struct {
+	Tokens []PersonalAccessToken `json:"tokens"`
+}
+
+type PersonalAccessTokenResponse struct {
+	PersonalAccessToken
+	Token string `json:"token"`
+}
+
 type LoginSpec struct {
 	Username   string `json:"username,omitempty"`
 	Password   string `json:"password,omitempty"`
 	Token      string `json:"token,omitempty"`
 	KubeConfig string `json:"kubeconfig,omitempty"`
+	IDToken    string `json:"idToken,omitempty"`
+	// Cluster is the name of the cluster credentials are checked against and the issued token can be used with,
+	// the default cluster when empty.
+	Cluster string `json:"cluster,omitempty"`
+	// ClientCertificate is the verified client certificate of the TLS connection the login request came over.
+	// It is never read from the request body.
+	ClientCertificate *x509.Certificate `json:"-"`
 }
 
 type AuthResponse struct {
@@ -83,6 +197,10 @@ type TokenRefreshSpec struct {
 	JWEToken string `json:"jweToken"`
 }
 
+type RevokeUserSpec struct {
+	Username string `json:"username"`
+}
+
 type LoginModesResponse struct {
 	Modes []AuthenticationMode `json:"modes"`
 }
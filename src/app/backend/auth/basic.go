@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+)
+
+type basicAuthenticator struct {
+	username string
+	password string
+}
+
+func (self *basicAuthenticator) GetAuthInfo() (api.AuthInfo, error) {
+	return api.AuthInfo{
+		Username: self.username,
+		Password: self.password,
+	}, nil
+}
+
+func NewBasicAuthenticator(spec *authApi.LoginSpec) authApi.Authenticator {
+	return &basicAuthenticator{
+		username: spec.Username,
+		password: spec.Password,
+	}
+}
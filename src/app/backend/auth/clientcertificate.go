@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"crypto/x509"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/args"
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+)
+
+// systemPrefix starts names of users and groups reserved by Kubernetes, e.g. system:masters.
+const systemPrefix = "system:"
+
+// clientCertificateAuthenticator maps verified client certificate to user and groups the same way the API server
+// does, common name is the user and organizations are the groups. Private key of the certificate never reaches
+// the portal, so the identity is passed to the cluster with impersonation. Certificates are signed by the client CA
+// of the portal rather than of the cluster, so only organizations allowed with --tls-client-cert-group become
+// groups and system: users and groups are never impersonated.
+type clientCertificateAuthenticator struct {
+	certificate   *x509.Certificate
+	allowedGroups []string
+}
+
+func (self *clientCertificateAuthenticator) GetAuthInfo() (api.AuthInfo, error) {
+	if self.certificate == nil || len(self.certificate.Subject.CommonName) == 0 {
+		return api.AuthInfo{}, errors.NewUnauthorized("client certificate does not contain common name")
+	}
+
+	if strings.HasPrefix(self.certificate.Subject.CommonName, systemPrefix) {
+		return api.AuthInfo{}, errors.NewUnauthorized("client certificate can not authenticate system users")
+	}
+
+	groups := make([]string, 0, len(self.certificate.Subject.Organization))
+	for _, group := range self.certificate.Subject.Organization {
+		if self.isAllowed(group) {
+			groups = append(groups, group)
+		}
+	}
+
+	return api.AuthInfo{
+		Impersonate:       self.certificate.Subject.CommonName,
+		ImpersonateGroups: groups,
+	}, nil
+}
+
+func (self *clientCertificateAuthenticator) isAllowed(group string) bool {
+	if strings.HasPrefix(group, systemPrefix) {
+		return false
+	}
+
+	for _, allowed := range self.allowedGroups {
+		if allowed == group {
+			return true
+		}
+	}
+
+	return false
+}
+
+func NewClientCertificateAuthenticator(spec *authApi.LoginSpec) authApi.Authenticator {
+	return &clientCertificateAuthenticator{
+		certificate:   spec.ClientCertificate,
+		allowedGroups: args.Holder.GetTlsClientCertGroups(),
+	}
+}
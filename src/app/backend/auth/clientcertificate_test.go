@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"reflect"
+	"testing"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/args"
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+)
+
+func TestClientCertificateAuthenticator(t *testing.T) {
+	cases := []struct {
+		subject  pkix.Name
+		expected api.AuthInfo
+		err      bool
+	}{
+		{
+			pkix.Name{CommonName: "jane", Organization: []string{"oncall", "developers"}},
+			api.AuthInfo{Impersonate: "jane", ImpersonateGroups: []string{"oncall"}},
+			false,
+		},
+		{
+			pkix.Name{CommonName: "jane", Organization: []string{"system:masters", "oncall"}},
+			api.AuthInfo{Impersonate: "jane", ImpersonateGroups: []string{"oncall"}},
+			false,
+		},
+		{
+			pkix.Name{CommonName: "jane"},
+			api.AuthInfo{Impersonate: "jane", ImpersonateGroups: []string{}},
+			false,
+		},
+		{pkix.Name{Organization: []string{"oncall"}}, api.AuthInfo{}, true},
+		{pkix.Name{CommonName: "system:admin", Organization: []string{"oncall"}}, api.AuthInfo{}, true},
+	}
+
+	// Allowing system: groups explicitly must not make them pass either.
+	args.GetHolderBuilder().SetTlsClientCertGroups([]string{"oncall", "system:masters"})
+	defer args.GetHolderBuilder().SetTlsClientCertGroups(nil)
+
+	for _, c := range cases {
+		spec := &authApi.LoginSpec{ClientCertificate: &x509.Certificate{Subject: c.subject}}
+		actual, err := NewClientCertificateAuthenticator(spec).GetAuthInfo()
+		if c.err {
+			if err == nil {
+				t.Errorf("GetAuthInfo(%+v): expected error but got %+v", c.subject, actual)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("GetAuthInfo(%+v): expected no error but got: %s", c.subject, err)
+		}
+
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetAuthInfo(%+v): expected %+v but got %+v", c.subject, c.expected, actual)
+		}
+	}
+}
@@ -1,18 +1,22 @@
 package auth
 
 import (
-	"log"
 	"net/http"
 
 	restfulspec "github.com/emicklei/go-restful-openapi/v2"
 	"github.com/emicklei/go-restful/v3"
 
+	"github.com/donghoon-khan/kubeportal/src/app/backend/args"
 	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
 	"github.com/donghoon-khan/kubeportal/src/app/backend/docs"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes"
+	k8sApi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
 )
 
 type AuthHandler struct {
-	manager authApi.AuthManager
+	manager  authApi.AuthManager
+	kManager k8sApi.KubernetesManager
 }
 
 func (authHandler AuthHandler) Install(ws *restful.WebService) {
@@ -23,7 +27,45 @@ func (authHandler AuthHandler) Install(ws *restful.WebService) {
 			Writes(authApi.AuthResponse{}).
 			Doc("Get JWEToken by LoginSpec").
 			Metadata(restfulspec.KeyOpenAPITags, docs.AuthenticationDocsTag).
-			Returns(200, "OK", authApi.AuthResponse{}))
+			Returns(200, "OK", authApi.AuthResponse{}).
+			Returns(400, "Bad Request", errors.StatusErrorResponse{}))
+	ws.Route(
+		ws.GET("/login/modes").
+			To(authHandler.handleLoginModes).
+			Writes(authApi.LoginModesResponse{}).
+			Doc("List enabled authentication modes").
+			Metadata(restfulspec.KeyOpenAPITags, docs.AuthenticationDocsTag).
+			Returns(200, "OK", authApi.LoginModesResponse{}))
+	ws.Route(
+		ws.POST("/token/refresh").
+			To(authHandler.handleJWETokenRefresh).
+			Reads(authApi.TokenRefreshSpec{}).
+			Writes(authApi.AuthResponse{}).
+			Doc("Replace JWEToken that has not expired yet with a new one").
+			Metadata(restfulspec.KeyOpenAPITags, docs.AuthenticationDocsTag).
+			Returns(200, "OK", authApi.AuthResponse{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}))
+	ws.Route(
+		ws.POST("/logout").
+			To(authHandler.handleLogout).
+			Param(ws.HeaderParameter(kubernetes.JWETokenHeader, "JWE token to revoke").Required(true)).
+			Doc("Revoke JWEToken, so that it can not be used anymore").
+			Metadata(restfulspec.KeyOpenAPITags, docs.AuthenticationDocsTag).
+			Returns(200, "OK", nil).
+			Returns(400, "Bad Request", errors.StatusErrorResponse{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}))
+	ws.Route(
+		ws.POST("/revoke").
+			To(authHandler.handleRevokeUser).
+			Reads(authApi.RevokeUserSpec{}).
+			Doc("Revoke all JWETokens issued to the user so far and delete personal access tokens of the user. "+
+				"Requires permission to update the "+
+				authApi.RevocationHolderSecretName+" secret").
+			Metadata(restfulspec.KeyOpenAPITags, docs.AuthenticationDocsTag).
+			Returns(200, "OK", nil).
+			Returns(400, "Bad Request", errors.StatusErrorResponse{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Returns(403, "Forbidden", errors.StatusErrorResponse{}))
 	ws.Route(
 		ws.GET("/login/skippable").
 			To(authHandler.handleLoginSkippable).
@@ -33,8 +75,107 @@ func (authHandler AuthHandler) Install(ws *restful.WebService) {
 			Returns(200, "OK", authApi.LoginSkippableResponse{}))
 }
 
-func (authHandler AuthHandler) handleLogin(request *restful.Request, resposne *restful.Response) {
-	log.Println("Handle Login")
+func (authHandler AuthHandler) handleLogin(request *restful.Request, response *restful.Response) {
+	loginSpec := new(authApi.LoginSpec)
+	if err := request.ReadEntity(loginSpec); err != nil {
+		errors.HandleInternalError(response, errors.NewBadRequest(err.Error()))
+		return
+	}
+
+	// Only certificates verified against the client CA during the handshake are taken into account.
+	if tlsState := request.Request.TLS; tlsState != nil && len(tlsState.VerifiedChains) > 0 {
+		loginSpec.ClientCertificate = tlsState.VerifiedChains[0][0]
+	}
+
+	loginResponse, err := authHandler.manager.Login(loginSpec)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	request.SetAttribute(authApi.LoginSucceededAttribute, len(loginResponse.Errors) == 0)
+
+	response.WriteHeaderAndEntity(http.StatusOK, loginResponse)
+}
+
+func (authHandler AuthHandler) handleJWETokenRefresh(request *restful.Request, response *restful.Response) {
+	tokenRefreshSpec := new(authApi.TokenRefreshSpec)
+	if err := request.ReadEntity(tokenRefreshSpec); err != nil {
+		errors.HandleInternalError(response, errors.NewBadRequest(err.Error()))
+		return
+	}
+
+	refreshedJWEToken, err := authHandler.manager.Refresh(tokenRefreshSpec.JWEToken)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, &authApi.AuthResponse{
+		JWEToken: refreshedJWEToken,
+		Errors:   make([]error, 0),
+	})
+}
+
+func (authHandler *AuthHandler) handleLogout(request *restful.Request, response *restful.Response) {
+	if err := authHandler.manager.Logout(request.HeaderParameter(kubernetes.JWETokenHeader)); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+}
+
+func (authHandler *AuthHandler) handleRevokeUser(request *restful.Request, response *restful.Response) {
+	spec := new(authApi.RevokeUserSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		errors.HandleInternalError(response, errors.NewBadRequest(err.Error()))
+		return
+	}
+
+	// With skippable login, requests without credentials are made with the portal's own ones, which must never
+	// authorize revocations.
+	authInfo, err := authHandler.kManager.AuthInfo(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	if authInfo == nil {
+		errors.HandleInternalError(response, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError))
+		return
+	}
+
+	// Only users that could tamper with the revocations directly are allowed to revoke tokens of others.
+	ssar, err := k8sApi.ToKindSelfSubjectAccessReview(args.Holder.GetNamespace(),
+		authApi.RevocationHolderSecretName, "secret", "", "update")
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	if _, err = authHandler.kManager.Config(request); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	if !authHandler.kManager.CanI(request, ssar) {
+		errors.HandleInternalError(response, errors.NewGenericResponse(http.StatusForbidden,
+			"not allowed to revoke tokens of other users"))
+		return
+	}
+
+	if err = authHandler.manager.RevokeUser(spec.Username); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+}
+
+func (authHandler *AuthHandler) handleLoginModes(request *restful.Request, response *restful.Response) {
+	response.WriteHeaderAndEntity(http.StatusOK,
+		authApi.LoginModesResponse{Modes: authHandler.manager.AuthenticationModes()})
 }
 
 func (authHandler *AuthHandler) handleLoginSkippable(request *restful.Request, response *restful.Response) {
@@ -42,6 +183,6 @@ func (authHandler *AuthHandler) handleLoginSkippable(request *restful.Request, r
 		authApi.LoginSkippableResponse{Skippable: authHandler.manager.AuthenticationSkippable()})
 }
 
-func NewAuthHandler(manager authApi.AuthManager) AuthHandler {
-	return AuthHandler{manager: manager}
+func NewAuthHandler(manager authApi.AuthManager, kManager k8sApi.KubernetesManager) AuthHandler {
+	return AuthHandler{manager: manager, kManager: kManager}
 }
@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emicklei/go-restful/v3"
+	v1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+	k8sApi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
+)
+
+type fakeAuthManager struct {
+	refreshErr error
+	logoutErr  error
+	loggedOut  string
+	revoked    string
+}
+
+func (self *fakeAuthManager) Login(*authApi.LoginSpec) (*authApi.AuthResponse, error) {
+	return &authApi.AuthResponse{}, nil
+}
+
+func (self *fakeAuthManager) Refresh(string) (string, error) {
+	return "refreshed", self.refreshErr
+}
+
+func (self *fakeAuthManager) Logout(jweToken string) error {
+	self.loggedOut = jweToken
+	return self.logoutErr
+}
+
+func (self *fakeAuthManager) RevokeUser(username string) error {
+	self.revoked = username
+	return nil
+}
+
+func (self *fakeAuthManager) AuthenticationModes() []authApi.AuthenticationMode {
+	return []authApi.AuthenticationMode{authApi.Token}
+}
+
+func (self *fakeAuthManager) AuthenticationSkippable() bool {
+	return false
+}
+
+type fakeKubernetesManager struct {
+	k8sApi.KubernetesManager
+	client   kubernetes.Interface
+	authInfo *api.AuthInfo
+	allowed  bool
+}
+
+func (self *fakeKubernetesManager) InsecureKubernetes() kubernetes.Interface {
+	return self.client
+}
+
+func (self *fakeKubernetesManager) AuthInfo(*restful.Request) (*api.AuthInfo, error) {
+	return self.authInfo, nil
+}
+
+func (self *fakeKubernetesManager) Config(*restful.Request) (*rest.Config, error) {
+	return &rest.Config{}, nil
+}
+
+func (self *fakeKubernetesManager) CanI(*restful.Request, *v1.SelfSubjectAccessReview) bool {
+	return self.allowed
+}
+
+func newTestContainer(manager authApi.AuthManager) *restful.Container {
+	return newTestContainerWithKubernetes(manager, nil)
+}
+
+func newTestContainerWithKubernetes(manager authApi.AuthManager, kManager k8sApi.KubernetesManager) *restful.Container {
+	ws := new(restful.WebService)
+	ws.Path("/api/v1/authentication").
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON)
+	NewAuthHandler(manager, kManager).Install(ws)
+
+	container := restful.NewContainer()
+	container.Add(ws)
+	return container
+}
+
+func TestHandleJWETokenRefresh(t *testing.T) {
+	cases := []struct {
+		refreshErr       error
+		expectedCode     int
+		expectedContains string
+	}{
+		{nil, http.StatusOK, `"jweToken": "refreshed"`},
+		{errors.NewTokenExpired(errors.MsgTokenExpiredError), http.StatusUnauthorized, errors.MsgTokenExpiredError},
+	}
+
+	for _, c := range cases {
+		container := newTestContainer(&fakeAuthManager{refreshErr: c.refreshErr})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/authentication/token/refresh",
+			strings.NewReader(`{"jweToken": "token"}`))
+		req.Header.Set("Content-Type", restful.MIME_JSON)
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, req)
+
+		if recorder.Code != c.expectedCode {
+			t.Errorf("handleJWETokenRefresh(): expected status %d but got %d", c.expectedCode, recorder.Code)
+		}
+
+		if !strings.Contains(recorder.Body.String(), c.expectedContains) {
+			t.Errorf("handleJWETokenRefresh(): expected body to contain %s but got %s", c.expectedContains,
+				recorder.Body.String())
+		}
+	}
+}
+
+func TestHandleLoginModes(t *testing.T) {
+	container := newTestContainer(&fakeAuthManager{})
+	recorder := httptest.NewRecorder()
+	container.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/authentication/login/modes", nil))
+
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"token"`) {
+		t.Errorf("handleLoginModes(): expected token mode to be listed but got %d %s", recorder.Code,
+			recorder.Body.String())
+	}
+}
+
+func TestHandleLogout(t *testing.T) {
+	cases := []struct {
+		logoutErr    error
+		expectedCode int
+	}{
+		{nil, http.StatusOK},
+		{errors.NewUnauthorized(errors.MsgTokenRevokedError), http.StatusUnauthorized},
+	}
+
+	for _, c := range cases {
+		manager := &fakeAuthManager{logoutErr: c.logoutErr}
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/authentication/logout", nil)
+		req.Header.Set("Content-Type", restful.MIME_JSON)
+		req.Header.Set("jweToken", "token")
+		recorder := httptest.NewRecorder()
+		newTestContainer(manager).ServeHTTP(recorder, req)
+
+		if recorder.Code != c.expectedCode {
+			t.Errorf("handleLogout(): expected status %d but got %d", c.expectedCode, recorder.Code)
+		}
+
+		if manager.loggedOut != "token" {
+			t.Errorf("handleLogout(): expected token from the header to be revoked but got %q", manager.loggedOut)
+		}
+	}
+}
+
+func TestHandleRevokeUser(t *testing.T) {
+	cases := []struct {
+		authInfo     *api.AuthInfo
+		allowed      bool
+		expectedCode int
+		expected     string
+	}{
+		// Skippable login serves requests without credentials with the portal's own ones.
+		{nil, true, http.StatusUnauthorized, ""},
+		{&api.AuthInfo{Token: "token"}, false, http.StatusForbidden, ""},
+		{&api.AuthInfo{Token: "token"}, true, http.StatusOK, "jane"},
+	}
+
+	for _, c := range cases {
+		manager := &fakeAuthManager{}
+		kManager := &fakeKubernetesManager{authInfo: c.authInfo, allowed: c.allowed}
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/authentication/revoke",
+			strings.NewReader(`{"username": "jane"}`))
+		req.Header.Set("Content-Type", restful.MIME_JSON)
+		recorder := httptest.NewRecorder()
+		newTestContainerWithKubernetes(manager, kManager).ServeHTTP(recorder, req)
+
+		if recorder.Code != c.expectedCode || manager.revoked != c.expected {
+			t.Errorf("handleRevokeUser(%+v): expected status %d revoking %q but got %d revoking %q", c.authInfo,
+				c.expectedCode, c.expected, recorder.Code, manager.revoked)
+		}
+	}
+}
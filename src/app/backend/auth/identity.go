@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"context"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+)
+
+// ResolveIdentity returns user and groups the auth info authenticates as. Bearer tokens are resolved with a token
+// review created by the given client, which has to be allowed to create them.
+func ResolveIdentity(client kubernetes.Interface, authInfo api.AuthInfo) (*authApi.Identity, error) {
+	if len(authInfo.Impersonate) > 0 {
+		return &authApi.Identity{Username: authInfo.Impersonate, Groups: authInfo.ImpersonateGroups}, nil
+	}
+
+	if len(authInfo.Token) == 0 {
+		return nil, errors.NewBadRequest("identity can only be resolved for tokens and impersonated users")
+	}
+
+	review, err := client.AuthenticationV1().TokenReviews().Create(context.TODO(),
+		&authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: authInfo.Token}},
+		metaV1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if !review.Status.Authenticated {
+		return nil, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	return &authApi.Identity{Username: review.Status.User.Username, Groups: review.Status.User.Groups}, nil
+}
@@ -0,0 +1,318 @@
+package jwe
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"log"
+	"sync"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+)
+
+const (
+	holderMapKeyEntry  = "priv"
+	holderMapCertEntry = "pub"
+	keySize            = 2048
+	// RotatedAtAnnotation of the key holder secret is the time when the stored key has been generated.
+	RotatedAtAnnotation = "kubeportal.io/rotated-at"
+	// Replicas check the age of the stored key in this interval, or in the rotation interval when it is shorter.
+	rotationCheckInterval = time.Minute
+)
+
+// KeyHolder is responsible for generating, storing and synchronizing encryption key used for token
+// generation/decryption. The key is persisted in the EncryptionKeyHolderName secret so that every replica
+// works with the same key.
+type KeyHolder interface {
+	// Encrypter returns encrypter that is based on currently held key.
+	Encrypter() (jose.Encrypter, error)
+	// Key returns encryption key that is currently held.
+	Key() *rsa.PrivateKey
+	// Refresh reloads encryption key from the key holder secret.
+	Refresh()
+	// Recycle generates new encryption key and replaces the one stored in the key holder secret.
+	// Tokens generated with the old key can not be decrypted anymore.
+	Recycle() error
+	// Rotate recycles the stored key when it is older than maxAge. Only one of the replicas rotating at the
+	// same time recycles the key, the others pick it up.
+	Rotate(maxAge time.Duration) error
+}
+
+type rsaKeyHolder struct {
+	mux        sync.Mutex
+	key        *rsa.PrivateKey
+	kubernetes kubernetes.Interface
+	namespace  string
+	now        func() time.Time
+}
+
+func (self *rsaKeyHolder) Encrypter() (jose.Encrypter, error) {
+	publicKey := &self.Key().PublicKey
+	return jose.NewEncrypter(jose.A256GCM, jose.Recipient{Algorithm: jose.RSA_OAEP_256, Key: publicKey}, nil)
+}
+
+func (self *rsaKeyHolder) Key() *rsa.PrivateKey {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	return self.key
+}
+
+func (self *rsaKeyHolder) Refresh() {
+	secret, err := self.getSecret()
+	if err != nil {
+		log.Printf("Could not refresh encryption key. Reason: %s", err)
+		return
+	}
+
+	key, err := ParseRSAKey(string(secret.Data[holderMapKeyEntry]), string(secret.Data[holderMapCertEntry]))
+	if err != nil {
+		log.Printf("Could not parse encryption key from %s secret. Reason: %s",
+			authApi.EncryptionKeyHolderName, err)
+		return
+	}
+
+	self.setKey(key)
+}
+
+func (self *rsaKeyHolder) Recycle() error {
+	key, err := rsa.GenerateKey(rand.Reader, keySize)
+	if err != nil {
+		return err
+	}
+
+	secret, err := self.getSecret()
+	if errors.IsNotFound(err) {
+		return self.store(nil, key)
+	}
+	if err != nil {
+		return err
+	}
+
+	return self.store(secret, key)
+}
+
+func (self *rsaKeyHolder) Rotate(maxAge time.Duration) error {
+	secret, err := self.getSecret()
+	if errors.IsNotFound(err) {
+		return self.Recycle()
+	}
+	if err != nil {
+		return err
+	}
+
+	if self.now().Sub(rotatedAt(secret)) < maxAge {
+		key, err := ParseRSAKey(string(secret.Data[holderMapKeyEntry]), string(secret.Data[holderMapCertEntry]))
+		if err != nil {
+			return err
+		}
+
+		self.setKey(key)
+		return nil
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, keySize)
+	if err != nil {
+		return err
+	}
+
+	// Update is rejected with conflict when other replica has rotated the key in the meantime.
+	if err = self.store(secret, key); errors.IsConflict(err) {
+		self.Refresh()
+		return nil
+	}
+	return err
+}
+
+// store creates the key holder secret, or updates the given one, with the key.
+func (self *rsaKeyHolder) store(secret *v1.Secret, key *rsa.PrivateKey) error {
+	var err error
+	if secret == nil {
+		_, err = self.kubernetes.CoreV1().Secrets(self.namespace).Create(context.TODO(), self.toSecret(key),
+			metaV1.CreateOptions{})
+	} else {
+		updated := self.toSecret(key)
+		secret.Data = updated.Data
+		if secret.Annotations == nil {
+			secret.Annotations = make(map[string]string)
+		}
+		secret.Annotations[RotatedAtAnnotation] = updated.Annotations[RotatedAtAnnotation]
+		_, err = self.kubernetes.CoreV1().Secrets(self.namespace).Update(context.TODO(), secret, metaV1.UpdateOptions{})
+	}
+
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Encryption key stored in %s secret has been recycled", authApi.EncryptionKeyHolderName)
+	self.setKey(key)
+	return nil
+}
+
+func (self *rsaKeyHolder) setKey(key *rsa.PrivateKey) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	self.key = key
+}
+
+func (self *rsaKeyHolder) getSecret() (*v1.Secret, error) {
+	return self.kubernetes.CoreV1().Secrets(self.namespace).Get(context.TODO(), authApi.EncryptionKeyHolderName,
+		metaV1.GetOptions{})
+}
+
+func (self *rsaKeyHolder) toSecret(key *rsa.PrivateKey) *v1.Secret {
+	priv, pub := ExportRSAKeyOrDie(key)
+	return &v1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:        authApi.EncryptionKeyHolderName,
+			Namespace:   self.namespace,
+			Annotations: map[string]string{RotatedAtAnnotation: self.now().Format(time.RFC3339)},
+		},
+		Data: map[string][]byte{
+			holderMapKeyEntry:  []byte(priv),
+			holderMapCertEntry: []byte(pub),
+		},
+	}
+}
+
+func (self *rsaKeyHolder) init() {
+	secret, err := self.getSecret()
+	if err == nil {
+		key, err := ParseRSAKey(string(secret.Data[holderMapKeyEntry]), string(secret.Data[holderMapCertEntry]))
+		if err == nil {
+			log.Printf("Using encryption key stored in %s secret", authApi.EncryptionKeyHolderName)
+			self.setKey(key)
+			return
+		}
+
+		log.Printf("Encryption key stored in %s secret is invalid, generating a new one. Reason: %s",
+			authApi.EncryptionKeyHolderName, err)
+		if err = self.Recycle(); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	if !errors.IsNotFound(err) {
+		panic(err)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, keySize)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = self.kubernetes.CoreV1().Secrets(self.namespace).Create(context.TODO(), self.toSecret(key),
+		metaV1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		// Other replica has been faster, use its key.
+		self.Refresh()
+		return
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
+	log.Printf("Storing encryption key in %s secret", authApi.EncryptionKeyHolderName)
+	self.setKey(key)
+}
+
+// rotatedAt returns the time when the key of the secret has been generated. Secrets stored before the rotation
+// was introduced are as old as the secret.
+func rotatedAt(secret *v1.Secret) time.Time {
+	if value, exists := secret.Annotations[RotatedAtAnnotation]; exists {
+		if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+			return parsed
+		}
+	}
+
+	return secret.CreationTimestamp.Time
+}
+
+// StartRotation rotates the key of the holder once it is older than the interval, until stop is closed. Every
+// rotation invalidates tokens generated with the previous key, so the interval should be longer than the token
+// TTL.
+func StartRotation(holder KeyHolder, interval time.Duration, stop <-chan struct{}) {
+	checkInterval := rotationCheckInterval
+	if interval < checkInterval {
+		checkInterval = interval
+	}
+
+	go func() {
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := holder.Rotate(interval); err != nil {
+					log.Printf("Could not rotate encryption key. Reason: %s", err)
+				}
+			}
+		}
+	}()
+}
+
+// ParseRSAKey parses PEM encoded private and public keys into a single rsa.PrivateKey.
+func ParseRSAKey(privateKey, publicKey string) (*rsa.PrivateKey, error) {
+	privateBlock, _ := pem.Decode([]byte(privateKey))
+	if privateBlock == nil {
+		return nil, errors.NewInvalid("could not decode private key")
+	}
+
+	key, err := x509.ParsePKCS1PrivateKey(privateBlock.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	publicBlock, _ := pem.Decode([]byte(publicKey))
+	if publicBlock == nil {
+		return nil, errors.NewInvalid("could not decode public key")
+	}
+
+	pub, err := x509.ParsePKCS1PublicKey(publicBlock.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	key.PublicKey = *pub
+	return key, nil
+}
+
+// ExportRSAKeyOrDie returns PEM encoded private and public keys.
+func ExportRSAKeyOrDie(key *rsa.PrivateKey) (string, string) {
+	privateBytes := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+
+	publicBytes := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PUBLIC KEY",
+		Bytes: x509.MarshalPKCS1PublicKey(&key.PublicKey),
+	})
+
+	return string(privateBytes), string(publicBytes)
+}
+
+// NewRSAKeyHolder creates KeyHolder that stores its key in the EncryptionKeyHolderName secret in the given
+// namespace. If the secret does not exist yet, new key is generated and stored.
+func NewRSAKeyHolder(kubernetes kubernetes.Interface, namespace string) KeyHolder {
+	holder := &rsaKeyHolder{
+		kubernetes: kubernetes,
+		namespace:  namespace,
+		now:        time.Now,
+	}
+
+	holder.init()
+	return holder
+}
@@ -0,0 +1,284 @@
+package jwe
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+)
+
+// Claim is a key of additional authenticated data stored in the JWE token.
+type Claim string
+
+const (
+	// IAT is the time when token has been issued.
+	IAT Claim = "iat"
+	// EXP is the time when token expires.
+	EXP Claim = "exp"
+	// JTI is the unique ID of the token used to revoke it.
+	JTI Claim = "jti"
+	// SUB is the user the token has been issued to.
+	SUB Claim = "sub"
+	// CLS is the cluster the token has been issued for. It is not set for the default cluster.
+	CLS Claim = "cls"
+	// AUD is the URL path the URL token can be used with. Tokens with audience are never accepted in place of
+	// regular tokens.
+	AUD Claim = "aud"
+)
+
+const timeFormat = time.RFC3339
+
+type jweTokenManager struct {
+	keyHolder       KeyHolder
+	revocationStore RevocationStore
+	tokenTTL        time.Duration
+}
+
+func (self *jweTokenManager) Generate(authInfo api.AuthInfo, subject, cluster string) (string, error) {
+	claims, err := self.generateClaims(subject, cluster)
+	if err != nil {
+		return "", err
+	}
+
+	return self.encrypt(authInfo, claims)
+}
+
+func (self *jweTokenManager) GenerateURLToken(authInfo api.AuthInfo, path string, ttl time.Duration) (string,
+	error) {
+	if len(path) == 0 || ttl <= 0 {
+		return "", errors.NewBadRequest("URL token requires path and TTL")
+	}
+
+	claims, err := self.generateClaims("", "")
+	if err != nil {
+		return "", err
+	}
+
+	claims[AUD] = path
+	claims[EXP] = time.Now().Add(ttl).Format(timeFormat)
+	return self.encrypt(authInfo, claims)
+}
+
+func (self *jweTokenManager) Decrypt(jweToken, cluster string) (*api.AuthInfo, error) {
+	authInfo, claims, err := self.decrypt(jweToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Credentials of the token must never reach other cluster than the one they have been checked against.
+	if len(claims[AUD]) > 0 || claims[CLS] != cluster {
+		return nil, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	return authInfo, nil
+}
+
+func (self *jweTokenManager) DecryptURLToken(jweToken, path string) (*api.AuthInfo, error) {
+	authInfo, claims, err := self.decrypt(jweToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Expiry is checked even when regular tokens do not expire.
+	if claims[AUD] != path || self.isExpired(claims[EXP]) {
+		return nil, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	return authInfo, nil
+}
+
+func (self *jweTokenManager) Refresh(jweToken string) (string, error) {
+	if len(jweToken) == 0 {
+		return "", errors.NewBadRequest("can not refresh token, no token provided")
+	}
+
+	authInfo, claims, err := self.decrypt(jweToken)
+	if err != nil {
+		return "", err
+	}
+
+	if len(claims[AUD]) > 0 {
+		return "", errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	return self.Generate(*authInfo, claims[SUB], claims[CLS])
+}
+
+func (self *jweTokenManager) Revoke(jweToken string) error {
+	if len(jweToken) == 0 {
+		return errors.NewBadRequest("can not revoke token, no token provided")
+	}
+
+	_, claims, err := self.decrypt(jweToken)
+	if err != nil {
+		return err
+	}
+
+	if len(claims[JTI]) == 0 {
+		return errors.NewBadRequest("token has been issued without ID and can not be revoked")
+	}
+
+	// Tokens without expiry are revoked forever.
+	expiresAt, _ := time.Parse(timeFormat, claims[EXP])
+	return self.revocationStore.RevokeToken(claims[JTI], expiresAt)
+}
+
+func (self *jweTokenManager) RevokeSubject(subject string) error {
+	if len(subject) == 0 {
+		return errors.NewBadRequest("can not revoke tokens, no subject provided")
+	}
+
+	// Revocation is needed only until the last token issued so far expires.
+	expiresAt := time.Time{}
+	if self.tokenTTL > 0 {
+		expiresAt = time.Now().Add(self.tokenTTL)
+	}
+
+	return self.revocationStore.RevokeSubject(subject, expiresAt)
+}
+
+func (self *jweTokenManager) SetTokenTTL(ttl time.Duration) {
+	if ttl < 0 {
+		ttl = 0
+	}
+
+	self.tokenTTL = ttl
+}
+
+func (self *jweTokenManager) decrypt(jweToken string) (*api.AuthInfo, map[Claim]string, error) {
+	jweTokenObject, claims, err := self.validate(jweToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decrypted, err := jweTokenObject.Decrypt(self.keyHolder.Key())
+	if err == jose.ErrCryptoFailure {
+		// Key could have been recycled by other replica. Reload it and try again.
+		self.keyHolder.Refresh()
+		decrypted, err = jweTokenObject.Decrypt(self.keyHolder.Key())
+	}
+
+	if err != nil {
+		return nil, nil, errors.LocalizeError(err)
+	}
+
+	// Claims are authenticated by successful decryption, check revocation only now.
+	issuedAt, _ := time.Parse(timeFormat, claims[IAT])
+	if self.revocationStore.IsRevoked(claims[JTI], claims[SUB], issuedAt) {
+		return nil, nil, errors.NewUnauthorized(errors.MsgTokenRevokedError)
+	}
+
+	authInfo := new(api.AuthInfo)
+	if err = json.Unmarshal(decrypted, authInfo); err != nil {
+		return nil, nil, err
+	}
+
+	return authInfo, claims, nil
+}
+
+func (self *jweTokenManager) encrypt(authInfo api.AuthInfo, claims map[Claim]string) (string, error) {
+	marshalledAuthInfo, err := json.Marshal(authInfo)
+	if err != nil {
+		return "", err
+	}
+
+	marshalledClaims, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	// Cached key is used. Key recycled by other replica is picked up by the rotation or when decryption fails.
+	encrypter, err := self.keyHolder.Encrypter()
+	if err != nil {
+		return "", err
+	}
+
+	jweObject, err := encrypter.EncryptWithAuthData(marshalledAuthInfo, marshalledClaims)
+	if err != nil {
+		return "", err
+	}
+
+	return jweObject.FullSerialize(), nil
+}
+
+func (self *jweTokenManager) generateClaims(subject, cluster string) (map[Claim]string, error) {
+	tokenID := make([]byte, 16)
+	if _, err := rand.Read(tokenID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	claims := map[Claim]string{
+		IAT: now.Format(timeFormat),
+		JTI: hex.EncodeToString(tokenID),
+	}
+
+	if self.tokenTTL > 0 {
+		claims[EXP] = now.Add(self.tokenTTL).Format(timeFormat)
+	}
+
+	if len(subject) > 0 {
+		claims[SUB] = subject
+	}
+
+	if len(cluster) > 0 {
+		claims[CLS] = cluster
+	}
+
+	return claims, nil
+}
+
+func (self *jweTokenManager) validate(jweToken string) (*jose.JSONWebEncryption, map[Claim]string, error) {
+	jwe, err := jose.ParseEncrypted(jweToken)
+	if err != nil {
+		return nil, nil, errors.NewBadRequest(err.Error())
+	}
+
+	claims := map[Claim]string{}
+	if err = json.Unmarshal(jwe.GetAuthData(), &claims); err != nil {
+		return nil, nil, errors.NewBadRequest(err.Error())
+	}
+
+	if self.tokenTTL > 0 && self.isExpired(claims[EXP]) {
+		return nil, nil, errors.NewTokenExpired(errors.MsgTokenExpiredError)
+	}
+
+	return jwe, claims, nil
+}
+
+func (self *jweTokenManager) isExpired(exp string) bool {
+	if len(exp) == 0 {
+		// Token has been generated without TTL while TTL is now enabled.
+		return true
+	}
+
+	expiresAt, err := time.Parse(timeFormat, exp)
+	if err != nil {
+		return true
+	}
+
+	return time.Now().After(expiresAt)
+}
+
+// NewJWETokenManager creates TokenManager that encrypts AuthInfo into JWE tokens with the key provided by
+// the given KeyHolder. Tokens expire after authApi.DefaultTokenTTL seconds unless changed with SetTokenTTL.
+// Revocations are kept in memory of this replica only.
+func NewJWETokenManager(keyHolder KeyHolder) authApi.TokenManager {
+	return NewRevocableJWETokenManager(keyHolder, NewRevocationStore())
+}
+
+// NewRevocableJWETokenManager creates TokenManager like NewJWETokenManager that keeps revoked tokens in the
+// given RevocationStore.
+func NewRevocableJWETokenManager(keyHolder KeyHolder, revocationStore RevocationStore) authApi.TokenManager {
+	return &jweTokenManager{
+		keyHolder:       keyHolder,
+		revocationStore: revocationStore,
+		tokenTTL:        authApi.DefaultTokenTTL * time.Second,
+	}
+}
@@ -0,0 +1,262 @@
+package jwe_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/auth/jwe"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+)
+
+const namespace = "kube-portal"
+
+func TestJWETokenManager(t *testing.T) {
+	cases := []struct {
+		authInfo api.AuthInfo
+	}{
+		{api.AuthInfo{Token: "test-token"}},
+		{api.AuthInfo{Username: "test-user", Password: "test-password"}},
+	}
+
+	fakeClient := fake.NewSimpleClientset()
+	manager := jwe.NewJWETokenManager(jwe.NewRSAKeyHolder(fakeClient, namespace))
+	for _, c := range cases {
+		token, err := manager.Generate(c.authInfo, "", "")
+		if err != nil {
+			t.Fatalf("Generate(%v): Expected no error but got: %s", c.authInfo, err)
+		}
+
+		actual, err := manager.Decrypt(token, "")
+		if err != nil {
+			t.Fatalf("Decrypt(): Expected no error but got: %s", err)
+		}
+
+		if !reflect.DeepEqual(*actual, c.authInfo) {
+			t.Errorf("Decrypt(): Expected %v but got %v", c.authInfo, *actual)
+		}
+
+		refreshed, err := manager.Refresh(token)
+		if err != nil {
+			t.Fatalf("Refresh(): Expected no error but got: %s", err)
+		}
+
+		if actual, _ = manager.Decrypt(refreshed, ""); !reflect.DeepEqual(*actual, c.authInfo) {
+			t.Errorf("Decrypt(): Expected refreshed token to contain %v but got %v", c.authInfo, *actual)
+		}
+	}
+}
+
+func TestJWETokenManagerCluster(t *testing.T) {
+	manager := jwe.NewJWETokenManager(jwe.NewRSAKeyHolder(fake.NewSimpleClientset(), namespace))
+	token, err := manager.Generate(api.AuthInfo{Token: "test-token"}, "jane", "dev")
+	if err != nil {
+		t.Fatalf("Generate(): Expected no error but got: %s", err)
+	}
+
+	refreshed, err := manager.Refresh(token)
+	if err != nil {
+		t.Fatalf("Refresh(): Expected no error but got: %s", err)
+	}
+
+	for _, token := range []string{token, refreshed} {
+		if _, err = manager.Decrypt(token, "dev"); err != nil {
+			t.Errorf("Decrypt(dev): Expected no error but got: %s", err)
+		}
+
+		for _, cluster := range []string{"", "staging"} {
+			if _, err = manager.Decrypt(token, cluster); !errors.IsUnauthorized(err) {
+				t.Errorf("Decrypt(%s): Expected token of other cluster to be unauthorized but got: %v", cluster, err)
+			}
+		}
+	}
+
+	defaultToken, _ := manager.Generate(api.AuthInfo{Token: "test-token"}, "jane", "")
+	if _, err = manager.Decrypt(defaultToken, "dev"); !errors.IsUnauthorized(err) {
+		t.Errorf("Decrypt(dev): Expected token of the default cluster to be unauthorized but got: %v", err)
+	}
+}
+
+func TestJWETokenManagerURLToken(t *testing.T) {
+	manager := jwe.NewJWETokenManager(jwe.NewRSAKeyHolder(fake.NewSimpleClientset(), namespace))
+	manager.SetTokenTTL(0)
+	authInfo := api.AuthInfo{Token: "test-token"}
+	token, err := manager.GenerateURLToken(authInfo, "/api/v1/kubernetes/log/follow/default/pod/app", time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateURLToken(): Expected no error but got: %s", err)
+	}
+
+	actual, err := manager.DecryptURLToken(token, "/api/v1/kubernetes/log/follow/default/pod/app")
+	if err != nil || !reflect.DeepEqual(*actual, authInfo) {
+		t.Fatalf("DecryptURLToken(): Expected %v but got %v, %v", authInfo, actual, err)
+	}
+
+	_, err = manager.DecryptURLToken(token, "/api/v1/kubernetes/log/follow/default/pod/other")
+	if !errors.IsUnauthorized(err) {
+		t.Errorf("DecryptURLToken(): Expected token of other path to be unauthorized but got: %v", err)
+	}
+
+	if _, err = manager.Decrypt(token, ""); !errors.IsUnauthorized(err) {
+		t.Errorf("Decrypt(): Expected URL token to be unauthorized but got: %v", err)
+	}
+
+	if _, err = manager.Refresh(token); !errors.IsUnauthorized(err) {
+		t.Errorf("Refresh(): Expected URL token to be unauthorized but got: %v", err)
+	}
+
+	// URL tokens expire even when regular tokens do not.
+	expiring, _ := manager.GenerateURLToken(authInfo, "/path", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if _, err = manager.DecryptURLToken(expiring, "/path"); !errors.IsUnauthorized(err) {
+		t.Errorf("DecryptURLToken(): Expected expired token to be unauthorized but got: %v", err)
+	}
+}
+
+func TestJWETokenManagerExpiredToken(t *testing.T) {
+	manager := jwe.NewJWETokenManager(jwe.NewRSAKeyHolder(fake.NewSimpleClientset(), namespace))
+	manager.SetTokenTTL(time.Millisecond)
+
+	token, err := manager.Generate(api.AuthInfo{Token: "test-token"}, "", "")
+	if err != nil {
+		t.Fatalf("Generate(): Expected no error but got: %s", err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if _, err = manager.Decrypt(token, ""); !errors.IsTokenExpired(err) {
+		t.Errorf("Decrypt(): Expected token expired error but got: %v", err)
+	}
+
+	if _, err = manager.Refresh(token); !errors.IsTokenExpired(err) {
+		t.Errorf("Refresh(): Expected token expired error but got: %v", err)
+	}
+}
+
+func TestJWETokenManagerSharedKey(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	firstHolder := jwe.NewRSAKeyHolder(fakeClient, namespace)
+	first := jwe.NewJWETokenManager(firstHolder)
+	second := jwe.NewJWETokenManager(jwe.NewRSAKeyHolder(fakeClient, namespace))
+	authInfo := api.AuthInfo{Token: "test-token"}
+
+	token, _ := first.Generate(authInfo, "", "")
+	if _, err := second.Decrypt(token, ""); err != nil {
+		t.Fatalf("Decrypt(): Expected replicas to share the key but got: %s", err)
+	}
+
+	if err := firstHolder.Recycle(); err != nil {
+		t.Fatalf("Recycle(): Expected no error but got: %s", err)
+	}
+
+	// Second replica should pick up the recycled key.
+	recycledToken, _ := first.Generate(authInfo, "", "")
+	if _, err := second.Decrypt(recycledToken, ""); err != nil {
+		t.Fatalf("Decrypt(): Expected recycled key to be picked up but got: %s", err)
+	}
+
+	_, err := second.Decrypt(token, "")
+	if err == nil || err.Error() != errors.MsgEncryptionKeyChanged {
+		t.Errorf("Decrypt(): Expected %s error but got: %v", errors.MsgEncryptionKeyChanged, err)
+	}
+}
+
+func TestJWETokenManagerRotateKey(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	firstHolder := jwe.NewRSAKeyHolder(fakeClient, namespace)
+	secondHolder := jwe.NewRSAKeyHolder(fakeClient, namespace)
+	first := jwe.NewJWETokenManager(firstHolder)
+	second := jwe.NewJWETokenManager(secondHolder)
+	authInfo := api.AuthInfo{Token: "test-token"}
+
+	token, _ := first.Generate(authInfo, "", "")
+	if err := firstHolder.Rotate(time.Hour); err != nil {
+		t.Fatalf("Rotate(): Expected no error but got: %s", err)
+	}
+
+	if _, err := second.Decrypt(token, ""); err != nil {
+		t.Fatalf("Decrypt(): Expected key younger than max age to be kept but got: %s", err)
+	}
+
+	secrets := fakeClient.CoreV1().Secrets(namespace)
+	secret, _ := secrets.Get(context.TODO(), authApi.EncryptionKeyHolderName, metaV1.GetOptions{})
+	secret.Annotations[jwe.RotatedAtAnnotation] = time.Now().Add(-2 * time.Hour).Format(time.RFC3339)
+	if _, err := secrets.Update(context.TODO(), secret, metaV1.UpdateOptions{}); err != nil {
+		t.Fatalf("Update(): Expected no error but got: %s", err)
+	}
+
+	if err := firstHolder.Rotate(time.Hour); err != nil {
+		t.Fatalf("Rotate(): Expected no error but got: %s", err)
+	}
+
+	// Second replica picks up the rotated key instead of rotating it again.
+	if err := secondHolder.Rotate(time.Hour); err != nil {
+		t.Fatalf("Rotate(): Expected no error but got: %s", err)
+	}
+
+	rotatedToken, _ := first.Generate(authInfo, "", "")
+	if _, err := second.Decrypt(rotatedToken, ""); err != nil {
+		t.Fatalf("Decrypt(): Expected replicas to share the rotated key but got: %s", err)
+	}
+
+	if _, err := second.Decrypt(token, ""); err == nil || err.Error() != errors.MsgEncryptionKeyChanged {
+		t.Errorf("Decrypt(): Expected %s error but got: %v", errors.MsgEncryptionKeyChanged, err)
+	}
+}
+
+func TestJWETokenManagerRevoke(t *testing.T) {
+	manager := jwe.NewJWETokenManager(jwe.NewRSAKeyHolder(fake.NewSimpleClientset(), namespace))
+	authInfo := api.AuthInfo{Token: "test-token"}
+
+	loggedOut, _ := manager.Generate(authInfo, "jane", "")
+	revokedUser, _ := manager.Generate(authInfo, "jane", "")
+	otherUser, _ := manager.Generate(authInfo, "john", "")
+	if err := manager.Revoke(loggedOut); err != nil {
+		t.Fatalf("Revoke(): Expected no error but got: %s", err)
+	}
+
+	if _, err := manager.Decrypt(loggedOut, ""); err == nil || err.Error() != errors.MsgTokenRevokedError {
+		t.Errorf("Decrypt(): Expected %s error but got: %v", errors.MsgTokenRevokedError, err)
+	}
+
+	if _, err := manager.Refresh(loggedOut); err == nil || err.Error() != errors.MsgTokenRevokedError {
+		t.Errorf("Refresh(): Expected %s error but got: %v", errors.MsgTokenRevokedError, err)
+	}
+
+	if _, err := manager.Decrypt(revokedUser, ""); err != nil {
+		t.Fatalf("Decrypt(): Expected other tokens to stay valid but got: %s", err)
+	}
+
+	if err := manager.RevokeSubject("jane"); err != nil {
+		t.Fatalf("RevokeSubject(): Expected no error but got: %s", err)
+	}
+
+	if _, err := manager.Decrypt(revokedUser, ""); err == nil || err.Error() != errors.MsgTokenRevokedError {
+		t.Errorf("Decrypt(): Expected %s error but got: %v", errors.MsgTokenRevokedError, err)
+	}
+
+	if _, err := manager.Decrypt(otherUser, ""); err != nil {
+		t.Errorf("Decrypt(): Expected tokens of other users to stay valid but got: %s", err)
+	}
+}
+
+func TestJWETokenManagerSharedRevocations(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	first := jwe.NewRevocableJWETokenManager(jwe.NewRSAKeyHolder(fakeClient, namespace),
+		jwe.NewSecretRevocationStore(fakeClient, namespace))
+	second := jwe.NewRevocableJWETokenManager(jwe.NewRSAKeyHolder(fakeClient, namespace),
+		jwe.NewSecretRevocationStore(fakeClient, namespace))
+
+	token, _ := first.Generate(api.AuthInfo{Token: "test-token"}, "jane", "")
+	if err := first.Revoke(token); err != nil {
+		t.Fatalf("Revoke(): Expected no error but got: %s", err)
+	}
+
+	if _, err := second.Decrypt(token, ""); err == nil || err.Error() != errors.MsgTokenRevokedError {
+		t.Errorf("Decrypt(): Expected revocation to be shared by replicas but got: %v", err)
+	}
+}
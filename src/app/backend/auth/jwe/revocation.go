@@ -0,0 +1,149 @@
+package jwe
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/auth/store"
+)
+
+const (
+	revocationsEntry = "revocations"
+	// Revocations made by other replicas are picked up at most after this interval.
+	revocationSyncInterval = 5 * time.Second
+)
+
+// RevocationStore keeps track of tokens that have been revoked before they expired. Revocations are pruned once
+// the revoked tokens expire. Zero expiry means that the tokens never expire and the revocation is kept forever.
+type RevocationStore interface {
+	// RevokeToken revokes the token with the given ID.
+	RevokeToken(tokenID string, expiresAt time.Time) error
+	// RevokeSubject revokes all tokens issued to the subject until now.
+	RevokeSubject(subject string, expiresAt time.Time) error
+	// IsRevoked returns true when the token has been revoked either on its own or together with its subject.
+	IsRevoked(tokenID, subject string, issuedAt time.Time) bool
+}
+
+type subjectRevocation struct {
+	RevokedAt time.Time `json:"revokedAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type revocations struct {
+	Tokens   map[string]time.Time         `json:"tokens"`
+	Subjects map[string]subjectRevocation `json:"subjects"`
+}
+
+func newRevocations() *revocations {
+	return &revocations{
+		Tokens:   map[string]time.Time{},
+		Subjects: map[string]subjectRevocation{},
+	}
+}
+
+func (self *revocations) prune(now time.Time) {
+	for tokenID, expiresAt := range self.Tokens {
+		if !expiresAt.IsZero() && now.After(expiresAt) {
+			delete(self.Tokens, tokenID)
+		}
+	}
+
+	for subject, revocation := range self.Subjects {
+		if !revocation.ExpiresAt.IsZero() && now.After(revocation.ExpiresAt) {
+			delete(self.Subjects, subject)
+		}
+	}
+}
+
+// revocationCodec stores revocations as JSON in a single entry of the RevocationHolderSecretName secret.
+type revocationCodec struct{}
+
+func (self revocationCodec) Decode(data map[string][]byte) interface{} {
+	result := newRevocations()
+	if entry, exists := data[revocationsEntry]; exists {
+		if err := json.Unmarshal(entry, result); err != nil {
+			log.Printf("Revoked tokens stored in %s secret are invalid and will be replaced. Reason: %s",
+				authApi.RevocationHolderSecretName, err)
+			return newRevocations()
+		}
+	}
+
+	// Entries of the secret can be null after manual modifications.
+	if result.Tokens == nil {
+		result.Tokens = map[string]time.Time{}
+	}
+
+	if result.Subjects == nil {
+		result.Subjects = map[string]subjectRevocation{}
+	}
+
+	result.prune(time.Now())
+	return result
+}
+
+func (self revocationCodec) Encode(value interface{}) (map[string][]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string][]byte{revocationsEntry: data}, nil
+}
+
+// revocationStore keeps revocations in memory. When kubernetes client is given, revocations are also stored in
+// the RevocationHolderSecretName secret, so that they are shared by all replicas.
+type revocationStore struct {
+	store *store.SecretStore
+}
+
+func (self *revocationStore) RevokeToken(tokenID string, expiresAt time.Time) error {
+	return self.update(func(revocations *revocations) {
+		revocations.Tokens[tokenID] = expiresAt
+	})
+}
+
+func (self *revocationStore) RevokeSubject(subject string, expiresAt time.Time) error {
+	return self.update(func(revocations *revocations) {
+		revocations.Subjects[subject] = subjectRevocation{
+			RevokedAt: time.Now().Truncate(time.Second),
+			ExpiresAt: expiresAt,
+		}
+	})
+}
+
+func (self *revocationStore) IsRevoked(tokenID, subject string, issuedAt time.Time) bool {
+	revocations := self.store.Get(revocationSyncInterval).(*revocations)
+	if _, revoked := revocations.Tokens[tokenID]; revoked && len(tokenID) > 0 {
+		return true
+	}
+
+	revocation, revoked := revocations.Subjects[subject]
+	// Issue time has only second precision, tokens issued in the same second as the revocation are revoked too.
+	return revoked && len(subject) > 0 && !issuedAt.After(revocation.RevokedAt)
+}
+
+func (self *revocationStore) update(modify func(*revocations)) error {
+	return self.store.Update(func(value interface{}) error {
+		revocations := value.(*revocations)
+		modify(revocations)
+		revocations.prune(time.Now())
+		return nil
+	})
+}
+
+// NewRevocationStore creates RevocationStore that keeps revocations in memory of a single replica.
+func NewRevocationStore() RevocationStore {
+	return &revocationStore{store: store.NewSecretStore(nil, "", authApi.RevocationHolderSecretName,
+		revocationCodec{})}
+}
+
+// NewSecretRevocationStore creates RevocationStore that shares revocations between replicas through the
+// RevocationHolderSecretName secret in the given namespace.
+func NewSecretRevocationStore(kubernetes kubernetes.Interface, namespace string) RevocationStore {
+	return &revocationStore{store: store.NewSecretStore(kubernetes, namespace, authApi.RevocationHolderSecretName,
+		revocationCodec{})}
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+)
+
+// kubeConfigAuthenticator extracts credentials of the current context from the provided kubeconfig file.
+// Only credentials of the enabled authentication modes are taken into account. Other entries such as
+// exec plugins or token files are ignored, because they would be evaluated on the portal side.
+type kubeConfigAuthenticator struct {
+	fileContent         []byte
+	authenticationModes authApi.AuthenticationModes
+}
+
+func (self *kubeConfigAuthenticator) GetAuthInfo() (api.AuthInfo, error) {
+	config, err := clientcmd.Load(self.fileContent)
+	if err != nil {
+		return api.AuthInfo{}, errors.NewBadRequest(err.Error())
+	}
+
+	info, err := self.getCurrentAuthInfo(config)
+	if err != nil {
+		return api.AuthInfo{}, err
+	}
+
+	return self.getAuthInfo(info)
+}
+
+func (self *kubeConfigAuthenticator) getCurrentAuthInfo(config *api.Config) (*api.AuthInfo, error) {
+	if len(config.CurrentContext) == 0 {
+		return nil, errors.NewBadRequest("context not set in kubeconfig")
+	}
+
+	context, exists := config.Contexts[config.CurrentContext]
+	if !exists {
+		return nil, errors.NewBadRequest("context " + config.CurrentContext + " not found in kubeconfig")
+	}
+
+	info, exists := config.AuthInfos[context.AuthInfo]
+	if !exists {
+		return nil, errors.NewBadRequest("user " + context.AuthInfo + " not found in kubeconfig")
+	}
+
+	return info, nil
+}
+
+func (self *kubeConfigAuthenticator) getAuthInfo(info *api.AuthInfo) (api.AuthInfo, error) {
+	if len(info.Token) > 0 && self.authenticationModes.IsEnabled(authApi.Token) {
+		return api.AuthInfo{Token: info.Token}, nil
+	}
+
+	if len(info.Username) > 0 && len(info.Password) > 0 && self.authenticationModes.IsEnabled(authApi.Basic) {
+		return api.AuthInfo{Username: info.Username, Password: info.Password}, nil
+	}
+
+	return api.AuthInfo{}, errors.NewBadRequest("not enough data to create auth info structure")
+}
+
+func NewKubeConfigAuthenticator(spec *authApi.LoginSpec, authenticationModes authApi.AuthenticationModes) authApi.Authenticator {
+	return &kubeConfigAuthenticator{
+		fileContent:         []byte(spec.KubeConfig),
+		authenticationModes: authenticationModes,
+	}
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+)
+
+const kubeConfigTemplate = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://127.0.0.1:6443
+  name: test-cluster
+contexts:
+- context:
+    cluster: test-cluster
+    user: test-user
+  name: test-context
+current-context: test-context
+users:
+- name: test-user
+  user:
+`
+
+func TestKubeConfigAuthenticator(t *testing.T) {
+	cases := []struct {
+		info        string
+		modes       authApi.AuthenticationModes
+		expected    api.AuthInfo
+		expectedErr bool
+	}{
+		{
+			"    token: test-token\n",
+			authApi.AuthenticationModes{authApi.Token: true},
+			api.AuthInfo{Token: "test-token"},
+			false,
+		},
+		{
+			"    username: test-user\n    password: test-password\n",
+			authApi.AuthenticationModes{authApi.Basic: true},
+			api.AuthInfo{Username: "test-user", Password: "test-password"},
+			false,
+		},
+		{
+			"    username: test-user\n    password: test-password\n",
+			authApi.AuthenticationModes{authApi.Token: true},
+			api.AuthInfo{},
+			true,
+		},
+		{
+			"    tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token\n",
+			authApi.AuthenticationModes{authApi.Token: true, authApi.Basic: true},
+			api.AuthInfo{},
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		spec := &authApi.LoginSpec{KubeConfig: kubeConfigTemplate + c.info}
+		actual, err := NewKubeConfigAuthenticator(spec, c.modes).GetAuthInfo()
+		if (err != nil) != c.expectedErr {
+			t.Errorf("GetAuthInfo(): expected error %v but got %v", c.expectedErr, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetAuthInfo(): expected %v but got %v", c.expected, actual)
+		}
+	}
+}
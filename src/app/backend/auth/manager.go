@@ -1,17 +1,23 @@
 package auth
 
 import (
+	"k8s.io/client-go/tools/clientcmd/api"
+
 	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
 	k8sApi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
 )
 
 type authManager struct {
 	k8sManager              k8sApi.KubernetesManager
+	tokenManager            authApi.TokenManager
+	patManager              authApi.PersonalAccessTokenManager
+	idTokenVerifier         authApi.IDTokenVerifier
 	authenticationModes     authApi.AuthenticationModes
 	authenticationSkippable bool
 }
 
-/*func (self authManager) Login(spec *authApi.LoginSpec) (*authApi.AuthResponse, error) {
+func (self authManager) Login(spec *authApi.LoginSpec) (*authApi.AuthResponse, error) {
 	authenticator, err := self.getAuthenticator(spec)
 	if err != nil {
 		return nil, err
@@ -22,27 +28,43 @@ type authManager struct {
 		return nil, err
 	}
 
-	err = self.healthCheck(authInfo)
+	err = self.healthCheck(authInfo, spec.Cluster)
 	nonCriticalErrors, criticalError := errors.HandleError(err)
 	if criticalError != nil || len(nonCriticalErrors) > 0 {
 		return &authApi.AuthResponse{Errors: nonCriticalErrors}, criticalError
 	}
 
-	token, err := self.tokenManager.Generate(authInfo)
+	subject, err := self.subject(authInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := self.tokenManager.Generate(authInfo, subject, k8sApi.TokenCluster(spec.Cluster))
 	if err != nil {
 		return nil, err
 	}
 
 	return &authApi.AuthResponse{JWEToken: token, Errors: nonCriticalErrors}, nil
-}*/
-//TODO
-func (self authManager) Login(spec *authApi.LoginSpec) (*authApi.AuthResponse, error) {
-	return &authApi.AuthResponse{JWEToken: "JWEToken"}, nil
 }
 
-//TODO
 func (self authManager) Refresh(jweToken string) (string, error) {
-	return "RefreshToken", nil
+	return self.tokenManager.Refresh(jweToken)
+}
+
+func (self authManager) Logout(jweToken string) error {
+	return self.tokenManager.Revoke(jweToken)
+}
+
+func (self authManager) RevokeUser(username string) error {
+	if err := self.tokenManager.RevokeSubject(username); err != nil {
+		return err
+	}
+
+	if self.patManager == nil {
+		return nil
+	}
+
+	return self.patManager.RevokeOwner(username)
 }
 
 func (self authManager) AuthenticationModes() []authApi.AuthenticationMode {
@@ -53,10 +75,63 @@ func (self authManager) AuthenticationSkippable() bool {
 	return self.authenticationSkippable
 }
 
-func NewAuthManager(k8sManager k8sApi.KubernetesManager,
+func (self authManager) getAuthenticator(spec *authApi.LoginSpec) (authApi.Authenticator, error) {
+	if len(self.authenticationModes) == 0 {
+		return nil, errors.NewInvalid("all authentication modes are disabled")
+	}
+
+	switch {
+	case len(spec.Token) > 0 && self.authenticationModes.IsEnabled(authApi.Token):
+		return NewTokenAuthenticator(spec), nil
+	case len(spec.Username) > 0 && len(spec.Password) > 0 && self.authenticationModes.IsEnabled(authApi.Basic):
+		return NewBasicAuthenticator(spec), nil
+	case len(spec.IDToken) > 0 && self.authenticationModes.IsEnabled(authApi.OIDC) && self.idTokenVerifier != nil:
+		return NewOIDCAuthenticator(spec, self.idTokenVerifier), nil
+	case len(spec.KubeConfig) > 0:
+		return NewKubeConfigAuthenticator(spec, self.authenticationModes), nil
+	case spec.ClientCertificate != nil && self.authenticationModes.IsEnabled(authApi.ClientCertificate):
+		return NewClientCertificateAuthenticator(spec), nil
+	}
+
+	return nil, errors.NewBadRequest("not enough data to create authenticator")
+}
+
+// subject returns name of the user the auth info belongs to, so that tokens of the user can be revoked together.
+// Users of bearer tokens are resolved with a token review, so the portal's own service account has to be allowed
+// to create tokenreviews in the authentication.k8s.io group, otherwise logins with tokens fail.
+func (self authManager) subject(authInfo api.AuthInfo) (string, error) {
+	switch {
+	case len(authInfo.Impersonate) > 0:
+		return authInfo.Impersonate, nil
+	case len(authInfo.Username) > 0:
+		return authInfo.Username, nil
+	case len(authInfo.Token) == 0:
+		return "", nil
+	}
+
+	identity, err := ResolveIdentity(self.k8sManager.InsecureKubernetes(), authInfo)
+	if err != nil {
+		return "", err
+	}
+
+	return identity.Username, nil
+}
+
+func (self authManager) healthCheck(authInfo api.AuthInfo, cluster string) error {
+	return self.k8sManager.HasAccess(authInfo, cluster)
+}
+
+// NewAuthManager creates AuthManager. ID token verifier is required only when OIDC authentication mode is
+// enabled and can be nil otherwise. Personal access tokens of revoked users are deleted when the manager of the
+// tokens is given.
+func NewAuthManager(k8sManager k8sApi.KubernetesManager, tokenManager authApi.TokenManager,
+	patManager authApi.PersonalAccessTokenManager, idTokenVerifier authApi.IDTokenVerifier,
 	authenticationModes authApi.AuthenticationModes, authenticationSkippable bool) authApi.AuthManager {
 	return &authManager{
 		k8sManager:              k8sManager,
+		tokenManager:            tokenManager,
+		patManager:              patManager,
+		idTokenVerifier:         idTokenVerifier,
 		authenticationModes:     authenticationModes,
 		authenticationSkippable: authenticationSkippable,
 	}
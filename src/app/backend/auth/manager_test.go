@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"crypto/x509"
+	"reflect"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+)
+
+func TestGetAuthenticator(t *testing.T) {
+	cases := []struct {
+		spec     *authApi.LoginSpec
+		modes    authApi.AuthenticationModes
+		expected authApi.Authenticator
+	}{
+		{
+			&authApi.LoginSpec{Token: "test-token"},
+			authApi.AuthenticationModes{authApi.Token: true},
+			&tokenAuthenticator{token: "test-token"},
+		},
+		{
+			&authApi.LoginSpec{Username: "test-user", Password: "test-password"},
+			authApi.AuthenticationModes{authApi.Token: true, authApi.Basic: true},
+			&basicAuthenticator{username: "test-user", password: "test-password"},
+		},
+		{
+			&authApi.LoginSpec{Username: "test-user", Password: "test-password"},
+			authApi.AuthenticationModes{authApi.Token: true},
+			nil,
+		},
+		{
+			&authApi.LoginSpec{Token: "test-token"},
+			authApi.AuthenticationModes{},
+			nil,
+		},
+		{
+			&authApi.LoginSpec{ClientCertificate: &x509.Certificate{}},
+			authApi.AuthenticationModes{authApi.ClientCertificate: true},
+			&clientCertificateAuthenticator{certificate: &x509.Certificate{}},
+		},
+		{
+			&authApi.LoginSpec{ClientCertificate: &x509.Certificate{}},
+			authApi.AuthenticationModes{authApi.Token: true},
+			nil,
+		},
+	}
+
+	for _, c := range cases {
+		manager := authManager{authenticationModes: c.modes}
+		actual, err := manager.getAuthenticator(c.spec)
+		if c.expected == nil {
+			if err == nil {
+				t.Errorf("getAuthenticator(%+v): expected error but got %#v", c.spec, actual)
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("getAuthenticator(%+v): expected %#v but got %#v", c.spec, c.expected, actual)
+		}
+	}
+}
+
+func TestSubject(t *testing.T) {
+	cases := []struct {
+		authInfo  api.AuthInfo
+		reviewErr error
+		expected  string
+		err       bool
+	}{
+		{api.AuthInfo{Impersonate: "jane", Token: "token"}, nil, "jane", false},
+		{api.AuthInfo{Username: "john", Password: "password"}, nil, "john", false},
+		{api.AuthInfo{}, nil, "", false},
+		{api.AuthInfo{Token: "token"}, nil, "jane", false},
+		// Tokens that can not be revoked by user are not issued at all.
+		{api.AuthInfo{Token: "token"}, k8sErrors.NewForbidden(schema.GroupResource{Resource: "tokenreviews"}, "",
+			nil), "", true},
+	}
+
+	for _, c := range cases {
+		client := fake.NewSimpleClientset()
+		client.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			review := &authenticationv1.TokenReview{Status: authenticationv1.TokenReviewStatus{
+				Authenticated: true, User: authenticationv1.UserInfo{Username: "jane"}}}
+			return true, review, c.reviewErr
+		})
+
+		manager := authManager{k8sManager: &fakeKubernetesManager{client: client}}
+		actual, err := manager.subject(c.authInfo)
+		if (err != nil) != c.err || actual != c.expected {
+			t.Errorf("subject(%+v): expected %q with error %v but got %q with: %v", c.authInfo, c.expected, c.err,
+				actual, err)
+		}
+	}
+}
+
+type fakeTokenManager struct {
+	authApi.TokenManager
+	revoked string
+}
+
+func (self *fakeTokenManager) RevokeSubject(subject string) error {
+	self.revoked = subject
+	return nil
+}
+
+type fakePersonalAccessTokenManager struct {
+	authApi.PersonalAccessTokenManager
+	revoked string
+}
+
+func (self *fakePersonalAccessTokenManager) RevokeOwner(owner string) error {
+	self.revoked = owner
+	return nil
+}
+
+func TestRevokeUser(t *testing.T) {
+	tokenManager := &fakeTokenManager{}
+	patManager := &fakePersonalAccessTokenManager{}
+	manager := NewAuthManager(nil, tokenManager, patManager, nil, authApi.AuthenticationModes{}, false)
+
+	if err := manager.RevokeUser("jane"); err != nil {
+		t.Fatalf("RevokeUser(): expected no error but got: %s", err)
+	}
+
+	if tokenManager.revoked != "jane" || patManager.revoked != "jane" {
+		t.Errorf("RevokeUser(): expected tokens and personal access tokens of jane to be revoked but got %q and %q",
+			tokenManager.revoked, patManager.revoked)
+	}
+}
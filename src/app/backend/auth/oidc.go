@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+)
+
+// oidcAuthenticator verifies OpenID Connect ID token and passes identity to the cluster either as the ID
+// token itself or, when the cluster does not trust the issuer, as an impersonated user. Impersonation uses the
+// backend's own credentials, so system: users and groups supplied by the issuer are never impersonated. Names
+// with --oidc-username-prefix or --oidc-groups-prefix do not start with system: unless the prefix does.
+type oidcAuthenticator struct {
+	idToken  string
+	verifier authApi.IDTokenVerifier
+}
+
+func (self *oidcAuthenticator) GetAuthInfo() (api.AuthInfo, error) {
+	claims, err := self.verifier.Verify(self.idToken)
+	if err != nil {
+		return api.AuthInfo{}, err
+	}
+
+	if self.verifier.Impersonate() {
+		if strings.HasPrefix(claims.Username, systemPrefix) {
+			return api.AuthInfo{}, errors.NewUnauthorized("ID token can not authenticate system users")
+		}
+
+		groups := make([]string, 0, len(claims.Groups))
+		for _, group := range claims.Groups {
+			if !strings.HasPrefix(group, systemPrefix) {
+				groups = append(groups, group)
+			}
+		}
+
+		return api.AuthInfo{
+			Impersonate:       claims.Username,
+			ImpersonateGroups: groups,
+		}, nil
+	}
+
+	return api.AuthInfo{Token: self.idToken}, nil
+}
+
+func NewOIDCAuthenticator(spec *authApi.LoginSpec, verifier authApi.IDTokenVerifier) authApi.Authenticator {
+	return &oidcAuthenticator{
+		idToken:  spec.IDToken,
+		verifier: verifier,
+	}
+}
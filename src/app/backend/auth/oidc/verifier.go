@@ -0,0 +1,253 @@
+package oidc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+)
+
+const (
+	DefaultUsernameClaim = "sub"
+	DefaultGroupsClaim   = "groups"
+
+	// Allowed clock skew between the portal and the issuer.
+	clockSkew = time.Minute
+	// Minimal interval between two reloads of the key set triggered by unknown key ID.
+	keySetReloadInterval = time.Minute
+	keySetFetchTimeout   = 10 * time.Second
+)
+
+var supportedAlgorithms = map[jose.SignatureAlgorithm]bool{
+	jose.RS256: true, jose.RS384: true, jose.RS512: true,
+	jose.PS256: true, jose.PS384: true, jose.PS512: true,
+	jose.ES256: true, jose.ES384: true, jose.ES512: true,
+}
+
+// Config describes OpenID Connect issuer trusted by the portal.
+type Config struct {
+	IssuerURL string
+	ClientID  string
+	// Key set of the issuer can be read either from a file or from an URL.
+	JWKSFile string
+	JWKSURL  string
+
+	UsernameClaim  string
+	UsernamePrefix string
+	GroupsClaim    string
+	GroupsPrefix   string
+
+	// Impersonate makes the portal talk to the cluster as the verified user using impersonation instead of
+	// passing the ID token to the API server.
+	Impersonate bool
+}
+
+type idTokenVerifier struct {
+	config Config
+	client *http.Client
+
+	mux          sync.Mutex
+	keySet       *jose.JSONWebKeySet
+	lastReloaded time.Time
+}
+
+func (self *idTokenVerifier) Verify(rawIDToken string) (*authApi.IDTokenClaims, error) {
+	token, err := jwt.ParseSigned(rawIDToken)
+	if err != nil {
+		return nil, errors.NewUnauthorized(fmt.Sprintf("malformed ID token: %s", err))
+	}
+
+	if len(token.Headers) != 1 || !supportedAlgorithms[jose.SignatureAlgorithm(token.Headers[0].Algorithm)] {
+		return nil, errors.NewUnauthorized("ID token is signed with unsupported algorithm")
+	}
+
+	claims := jwt.Claims{}
+	extra := map[string]interface{}{}
+	if err = self.verifySignature(token, &claims, &extra); err != nil {
+		return nil, err
+	}
+
+	if claims.Expiry == nil {
+		return nil, errors.NewUnauthorized("ID token does not expire")
+	}
+
+	err = claims.ValidateWithLeeway(jwt.Expected{
+		Issuer:   self.config.IssuerURL,
+		Audience: jwt.Audience{self.config.ClientID},
+		Time:     time.Now(),
+	}, clockSkew)
+	if err != nil {
+		return nil, errors.NewUnauthorized(fmt.Sprintf("invalid ID token: %s", err))
+	}
+
+	return self.toIDTokenClaims(extra)
+}
+
+func (self *idTokenVerifier) Impersonate() bool {
+	return self.config.Impersonate
+}
+
+func (self *idTokenVerifier) verifySignature(token *jwt.JSONWebToken, dest ...interface{}) error {
+	keyID := token.Headers[0].KeyID
+	keys := self.getKeys(keyID)
+	if len(keys) == 0 && self.reloadKeySet() {
+		keys = self.getKeys(keyID)
+	}
+
+	for _, key := range keys {
+		if err := token.Claims(key.Key, dest...); err == nil {
+			return nil
+		}
+	}
+
+	return errors.NewUnauthorized("failed to verify ID token signature")
+}
+
+func (self *idTokenVerifier) toIDTokenClaims(extra map[string]interface{}) (*authApi.IDTokenClaims, error) {
+	username, ok := extra[self.config.UsernameClaim].(string)
+	if !ok || len(username) == 0 {
+		return nil, errors.NewUnauthorized(fmt.Sprintf("ID token does not contain %s claim",
+			self.config.UsernameClaim))
+	}
+
+	if self.config.UsernameClaim == "email" {
+		if verified, exists := extra["email_verified"]; exists && verified != true {
+			return nil, errors.NewUnauthorized("email of the ID token is not verified")
+		}
+	}
+
+	result := &authApi.IDTokenClaims{
+		Username: self.config.UsernamePrefix + username,
+		Groups:   make([]string, 0),
+	}
+
+	switch groups := extra[self.config.GroupsClaim].(type) {
+	case string:
+		result.Groups = append(result.Groups, self.config.GroupsPrefix+groups)
+	case []interface{}:
+		for _, group := range groups {
+			if name, ok := group.(string); ok {
+				result.Groups = append(result.Groups, self.config.GroupsPrefix+name)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (self *idTokenVerifier) getKeys(keyID string) []jose.JSONWebKey {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	if self.keySet == nil {
+		return nil
+	}
+
+	if len(keyID) == 0 {
+		return self.keySet.Keys
+	}
+
+	return self.keySet.Key(keyID)
+}
+
+// reloadKeySet reads key set again, so that keys rotated by the issuer are picked up. Returns true when the
+// key set has been reloaded.
+func (self *idTokenVerifier) reloadKeySet() bool {
+	self.mux.Lock()
+	if time.Since(self.lastReloaded) < keySetReloadInterval {
+		self.mux.Unlock()
+		return false
+	}
+	self.lastReloaded = time.Now()
+	self.mux.Unlock()
+
+	keySet, err := self.loadKeySet()
+	if err != nil {
+		log.Printf("Could not reload OIDC key set. Reason: %s", err)
+		return false
+	}
+
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	self.keySet = keySet
+	return true
+}
+
+func (self *idTokenVerifier) loadKeySet() (*jose.JSONWebKeySet, error) {
+	var data []byte
+	var err error
+	if len(self.config.JWKSFile) > 0 {
+		data, err = ioutil.ReadFile(self.config.JWKSFile)
+	} else {
+		data, err = self.fetchKeySet()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	keySet := new(jose.JSONWebKeySet)
+	if err = json.Unmarshal(data, keySet); err != nil {
+		return nil, err
+	}
+
+	return keySet, nil
+}
+
+func (self *idTokenVerifier) fetchKeySet() ([]byte, error) {
+	response, err := self.client.Get(self.config.JWKSURL)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d while fetching %s", response.StatusCode,
+			self.config.JWKSURL)
+	}
+
+	return ioutil.ReadAll(response.Body)
+}
+
+// NewIDTokenVerifier creates IDTokenVerifier for the issuer described by config. Key set is loaded
+// immediately, so that misconfiguration is detected on startup.
+func NewIDTokenVerifier(config Config) (authApi.IDTokenVerifier, error) {
+	if len(config.IssuerURL) == 0 || len(config.ClientID) == 0 {
+		return nil, errors.NewInvalid("OIDC issuer URL and client ID are required")
+	}
+
+	if len(config.JWKSFile) == 0 && len(config.JWKSURL) == 0 {
+		return nil, errors.NewInvalid("OIDC key set file or URL is required")
+	}
+
+	if len(config.UsernameClaim) == 0 {
+		config.UsernameClaim = DefaultUsernameClaim
+	}
+
+	if len(config.GroupsClaim) == 0 {
+		config.GroupsClaim = DefaultGroupsClaim
+	}
+
+	verifier := &idTokenVerifier{
+		config: config,
+		client: &http.Client{Timeout: keySetFetchTimeout},
+	}
+
+	keySet, err := verifier.loadKeySet()
+	if err != nil {
+		return nil, err
+	}
+
+	verifier.keySet = keySet
+	verifier.lastReloaded = time.Now()
+	return verifier, nil
+}
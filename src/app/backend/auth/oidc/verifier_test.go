@@ -0,0 +1,135 @@
+package oidc_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/auth/oidc"
+)
+
+const (
+	issuer   = "https://issuer.example.com"
+	clientID = "kube-portal"
+	keyID    = "test-key"
+)
+
+// stubIssuer serves key set of a single RSA key that is used to sign test ID tokens.
+type stubIssuer struct {
+	key    *rsa.PrivateKey
+	server *httptest.Server
+}
+
+func newStubIssuer(t *testing.T) *stubIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuer := &stubIssuer{key: key}
+	issuer.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &key.PublicKey, KeyID: keyID, Algorithm: string(jose.RS256), Use: "sig"},
+		}})
+	}))
+	return issuer
+}
+
+func (self *stubIssuer) sign(t *testing.T, key *rsa.PrivateKey, claims ...interface{}) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", keyID))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	builder := jwt.Signed(signer)
+	for _, c := range claims {
+		builder = builder.Claims(c)
+	}
+
+	token, err := builder.CompactSerialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+func TestIDTokenVerifier(t *testing.T) {
+	stub := newStubIssuer(t)
+	defer stub.server.Close()
+
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	now := time.Now()
+	validClaims := jwt.Claims{
+		Issuer:   issuer,
+		Subject:  "jane",
+		Audience: jwt.Audience{clientID},
+		Expiry:   jwt.NewNumericDate(now.Add(time.Hour)),
+		IssuedAt: jwt.NewNumericDate(now),
+	}
+	groups := map[string]interface{}{"groups": []string{"developers", "operators"}}
+
+	expiredClaims := validClaims
+	expiredClaims.Expiry = jwt.NewNumericDate(now.Add(-time.Hour))
+	otherAudienceClaims := validClaims
+	otherAudienceClaims.Audience = jwt.Audience{"other-client"}
+	otherIssuerClaims := validClaims
+	otherIssuerClaims.Issuer = "https://evil.example.com"
+
+	cases := []struct {
+		name     string
+		token    string
+		expected *authApi.IDTokenClaims
+	}{
+		{
+			"valid",
+			stub.sign(t, stub.key, validClaims, groups),
+			&authApi.IDTokenClaims{Username: "oidc:jane", Groups: []string{"oidc:developers", "oidc:operators"}},
+		},
+		{"expired", stub.sign(t, stub.key, expiredClaims), nil},
+		{"other audience", stub.sign(t, stub.key, otherAudienceClaims), nil},
+		{"other issuer", stub.sign(t, stub.key, otherIssuerClaims), nil},
+		{"signed with unknown key", stub.sign(t, otherKey, validClaims), nil},
+		{"malformed", "not-a-token", nil},
+	}
+
+	verifier, err := oidc.NewIDTokenVerifier(oidc.Config{
+		IssuerURL:      issuer,
+		ClientID:       clientID,
+		JWKSURL:        stub.server.URL,
+		UsernamePrefix: "oidc:",
+		GroupsPrefix:   "oidc:",
+	})
+	if err != nil {
+		t.Fatalf("NewIDTokenVerifier(): expected no error but got: %s", err)
+	}
+
+	for _, c := range cases {
+		actual, err := verifier.Verify(c.token)
+		if c.expected == nil {
+			if err == nil {
+				t.Errorf("Verify(%s): expected error but got %+v", c.name, actual)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Verify(%s): expected no error but got: %s", c.name, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Verify(%s): expected %+v but got %+v", c.name, c.expected, actual)
+		}
+	}
+}
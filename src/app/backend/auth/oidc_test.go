@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+)
+
+type fakeIDTokenVerifier struct {
+	impersonate bool
+	claims      authApi.IDTokenClaims
+}
+
+func (self *fakeIDTokenVerifier) Verify(rawIDToken string) (*authApi.IDTokenClaims, error) {
+	return &self.claims, nil
+}
+
+func (self *fakeIDTokenVerifier) Impersonate() bool {
+	return self.impersonate
+}
+
+func TestOIDCAuthenticator(t *testing.T) {
+	jane := authApi.IDTokenClaims{Username: "jane", Groups: []string{"developers", "system:masters"}}
+	cases := []struct {
+		impersonate bool
+		claims      authApi.IDTokenClaims
+		expected    api.AuthInfo
+		expectedErr bool
+	}{
+		{false, jane, api.AuthInfo{Token: "id-token"}, false},
+		{true, jane, api.AuthInfo{Impersonate: "jane", ImpersonateGroups: []string{"developers"}}, false},
+		{true, authApi.IDTokenClaims{Username: "oidc:jane", Groups: []string{"oidc:system:masters"}},
+			api.AuthInfo{Impersonate: "oidc:jane", ImpersonateGroups: []string{"oidc:system:masters"}}, false},
+		{true, authApi.IDTokenClaims{Username: "system:admin"}, api.AuthInfo{}, true},
+	}
+
+	for _, c := range cases {
+		spec := &authApi.LoginSpec{IDToken: "id-token"}
+		verifier := &fakeIDTokenVerifier{impersonate: c.impersonate, claims: c.claims}
+		actual, err := NewOIDCAuthenticator(spec, verifier).GetAuthInfo()
+		if (err != nil) != c.expectedErr {
+			t.Fatalf("GetAuthInfo(%+v): expected error to be %v but got: %v", c.claims, c.expectedErr, err)
+		}
+
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetAuthInfo(%+v): expected %+v but got %+v", c.claims, c.expected, actual)
+		}
+	}
+}
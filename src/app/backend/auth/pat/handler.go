@@ -0,0 +1,138 @@
+package pat
+
+import (
+	"net/http"
+	"strings"
+
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/auth"
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/docs"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes"
+	k8sApi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
+)
+
+type PersonalAccessTokenHandler struct {
+	manager  authApi.PersonalAccessTokenManager
+	kManager k8sApi.KubernetesManager
+}
+
+func (tokenHandler PersonalAccessTokenHandler) Install(ws *restful.WebService) {
+	ws.Route(
+		ws.GET("/accesstoken").
+			To(tokenHandler.handleList).
+			Writes(authApi.PersonalAccessTokenList{}).
+			Doc("List personal access tokens of the user").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.PersonalAccessTokenDocsTag}).
+			Returns(200, "OK", authApi.PersonalAccessTokenList{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}))
+	ws.Route(
+		ws.POST("/accesstoken").
+			To(tokenHandler.handleCreate).
+			Reads(authApi.PersonalAccessTokenSpec{}).
+			Writes(authApi.PersonalAccessTokenResponse{}).
+			Doc("Create personal access token of the user. The token is returned only once. Users logged in "+
+				"with basic auth can not create tokens").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.PersonalAccessTokenDocsTag}).
+			Returns(200, "OK", authApi.PersonalAccessTokenResponse{}).
+			Returns(400, "Bad Request", errors.StatusErrorResponse{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Returns(403, "Forbidden", errors.StatusErrorResponse{}))
+	ws.Route(
+		ws.DELETE("/accesstoken/{id}").
+			To(tokenHandler.handleRevoke).
+			Param(ws.PathParameter("id", "ID of the token").Required(true)).
+			Doc("Revoke personal access token of the user").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.PersonalAccessTokenDocsTag}).
+			Returns(200, "OK", nil).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Returns(404, "Not Found", errors.StatusErrorResponse{}))
+}
+
+func (tokenHandler PersonalAccessTokenHandler) handleList(request *restful.Request, response *restful.Response) {
+	identity, err := tokenHandler.identity(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := tokenHandler.manager.List(identity.Username)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (tokenHandler PersonalAccessTokenHandler) handleCreate(request *restful.Request, response *restful.Response) {
+	spec := new(authApi.PersonalAccessTokenSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		errors.HandleInternalError(response, errors.NewBadRequest(err.Error()))
+		return
+	}
+
+	identity, err := tokenHandler.identity(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := tokenHandler.manager.Create(*identity, *spec)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (tokenHandler PersonalAccessTokenHandler) handleRevoke(request *restful.Request, response *restful.Response) {
+	identity, err := tokenHandler.identity(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	if err = tokenHandler.manager.Revoke(identity.Username, request.PathParameter("id")); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusOK)
+}
+
+// identity returns the user bound to the request. Personal access tokens can not be used to manage tokens,
+// otherwise a leaked token could be used to issue new ones. Groups of users logged in with basic auth or with
+// client certificates of kubeconfig files are not known to the portal, so such users can not manage tokens either.
+func (tokenHandler PersonalAccessTokenHandler) identity(request *restful.Request) (*authApi.Identity, error) {
+	header := strings.Fields(request.HeaderParameter(kubernetes.AuthorizationHeader))
+	if len(header) == 2 && strings.HasPrefix(header[1], authApi.PersonalAccessTokenPrefix) {
+		return nil, errors.NewGenericResponse(http.StatusForbidden,
+			"personal access tokens can not be used to manage personal access tokens")
+	}
+
+	authInfo, err := tokenHandler.kManager.AuthInfo(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if authInfo == nil {
+		return nil, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	if len(authInfo.Token) == 0 && len(authInfo.Impersonate) == 0 {
+		return nil, errors.NewBadRequest("personal access tokens can only be managed by users logged in with " +
+			"a bearer token, OIDC or a client certificate of the portal")
+	}
+
+	return auth.ResolveIdentity(tokenHandler.kManager.InsecureKubernetes(), *authInfo)
+}
+
+func NewPersonalAccessTokenHandler(manager authApi.PersonalAccessTokenManager,
+	kManager k8sApi.KubernetesManager) PersonalAccessTokenHandler {
+	return PersonalAccessTokenHandler{manager: manager, kManager: kManager}
+}
@@ -0,0 +1,63 @@
+package pat
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emicklei/go-restful/v3"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	k8sApi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
+)
+
+type fakeKubernetesManager struct {
+	k8sApi.KubernetesManager
+	authInfo *api.AuthInfo
+}
+
+func (self *fakeKubernetesManager) AuthInfo(*restful.Request) (*api.AuthInfo, error) {
+	return self.authInfo, nil
+}
+
+func (self *fakeKubernetesManager) InsecureKubernetes() kubernetes.Interface {
+	return fake.NewSimpleClientset()
+}
+
+func TestHandleCreate(t *testing.T) {
+	cases := []struct {
+		authInfo      *api.AuthInfo
+		authorization string
+		expectedCode  int
+	}{
+		{&api.AuthInfo{Impersonate: "jane", ImpersonateGroups: []string{"developers"}}, "", http.StatusOK},
+		{nil, "", http.StatusUnauthorized},
+		// Groups of basic auth users are not known, tokens would impersonate them without the groups.
+		{&api.AuthInfo{Username: "jane", Password: "password"}, "", http.StatusBadRequest},
+		{&api.AuthInfo{Impersonate: "jane"}, "Bearer kpat_id_secret", http.StatusForbidden},
+	}
+
+	for _, c := range cases {
+		ws := new(restful.WebService)
+		ws.Path("/api/v1/authentication").Consumes(restful.MIME_JSON).Produces(restful.MIME_JSON)
+		NewPersonalAccessTokenHandler(NewPersonalAccessTokenManager(fake.NewSimpleClientset(), namespace),
+			&fakeKubernetesManager{authInfo: c.authInfo}).Install(ws)
+		container := restful.NewContainer()
+		container.Add(ws)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/authentication/accesstoken",
+			strings.NewReader(`{"name": "ci", "scopes": ["read"]}`))
+		req.Header.Set("Content-Type", restful.MIME_JSON)
+		req.Header.Set("Authorization", c.authorization)
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, req)
+
+		if recorder.Code != c.expectedCode {
+			t.Errorf("handleCreate(%+v): expected status %d but got %d %s", c.authInfo, c.expectedCode,
+				recorder.Code, recorder.Body.String())
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package pat
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/auth/store"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+)
+
+const (
+	DefaultExpiration = 7 * 24 * time.Hour
+	// Groups of the owner are captured when the token is created, the maximal lifetime limits how long removal of
+	// the owner from a group can be missed.
+	MaxExpiration = 30 * 24 * time.Hour
+	// Maximal number of tokens of a single user, keeps the secret holding the tokens small.
+	MaxTokensPerUser = 20
+
+	// Tokens created or revoked by other replicas are picked up at most after this interval.
+	syncInterval = 5 * time.Second
+	// Minimal interval between two reloads triggered by unknown token ID.
+	unknownTokenReloadInterval = time.Second
+	maxNameLength              = 63
+)
+
+// record is a token as stored in the AccessTokenHolderSecretName secret. The token itself is never stored, only
+// its hash.
+type record struct {
+	authApi.PersonalAccessToken
+	Owner  string   `json:"owner"`
+	Groups []string `json:"groups"`
+	Hash   string   `json:"hash"`
+}
+
+func (self *record) allows(method string) bool {
+	for _, scope := range self.Scopes {
+		switch {
+		case scope == authApi.WriteScope:
+			return true
+		case scope == authApi.ReadScope && (method == http.MethodGet || method == http.MethodHead):
+			return true
+		}
+	}
+
+	return false
+}
+
+type personalAccessTokenManager struct {
+	store *store.SecretStore
+	now   func() time.Time
+}
+
+func (self *personalAccessTokenManager) Create(owner authApi.Identity,
+	spec authApi.PersonalAccessTokenSpec) (*authApi.PersonalAccessTokenResponse, error) {
+	if err := validate(spec); err != nil {
+		return nil, err
+	}
+
+	expiration := DefaultExpiration
+	if spec.ExpirationSeconds > 0 {
+		expiration = time.Duration(spec.ExpirationSeconds) * time.Second
+	}
+
+	id, err := randomString(8, hex.EncodeToString)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := randomString(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return nil, err
+	}
+
+	now := self.now().Truncate(time.Second)
+	token := authApi.PersonalAccessTokenPrefix + id + "_" + secret
+	created := &record{
+		PersonalAccessToken: authApi.PersonalAccessToken{
+			ID:        id,
+			Name:      spec.Name,
+			Scopes:    spec.Scopes,
+			CreatedAt: now,
+			ExpiresAt: now.Add(expiration),
+		},
+		Owner:  owner.Username,
+		Groups: owner.Groups,
+		Hash:   hash(token),
+	}
+
+	err = self.update(func(records map[string]*record) error {
+		if len(ownedBy(records, owner.Username)) >= MaxTokensPerUser {
+			return errors.NewBadRequest(fmt.Sprintf("user can not have more than %d tokens", MaxTokensPerUser))
+		}
+
+		records[id] = created
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &authApi.PersonalAccessTokenResponse{PersonalAccessToken: created.PersonalAccessToken, Token: token},
+		nil
+}
+
+func (self *personalAccessTokenManager) List(owner string) (*authApi.PersonalAccessTokenList, error) {
+	result := &authApi.PersonalAccessTokenList{Tokens: make([]authApi.PersonalAccessToken, 0)}
+	for _, record := range ownedBy(self.records(syncInterval), owner) {
+		if self.now().Before(record.ExpiresAt) {
+			result.Tokens = append(result.Tokens, record.PersonalAccessToken)
+		}
+	}
+
+	// Creation time has only second precision, tokens created in the same second are sorted by name.
+	sort.Slice(result.Tokens, func(i, j int) bool {
+		if !result.Tokens[i].CreatedAt.Equal(result.Tokens[j].CreatedAt) {
+			return result.Tokens[i].CreatedAt.Before(result.Tokens[j].CreatedAt)
+		}
+
+		return result.Tokens[i].Name < result.Tokens[j].Name
+	})
+	return result, nil
+}
+
+func (self *personalAccessTokenManager) Revoke(owner, id string) error {
+	return self.update(func(records map[string]*record) error {
+		if record, exists := records[id]; !exists || record.Owner != owner {
+			return errors.NewNotFound(fmt.Sprintf("token %s not found", id))
+		}
+
+		delete(records, id)
+		return nil
+	})
+}
+
+func (self *personalAccessTokenManager) RevokeOwner(owner string) error {
+	return self.update(func(records map[string]*record) error {
+		for id, record := range records {
+			if record.Owner == owner {
+				delete(records, id)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (self *personalAccessTokenManager) Resolve(token, method string) (*api.AuthInfo, error) {
+	id := strings.SplitN(strings.TrimPrefix(token, authApi.PersonalAccessTokenPrefix), "_", 2)[0]
+	record := self.records(syncInterval)[id]
+	if record == nil {
+		// Token could have been created by other replica since the last sync.
+		record = self.records(unknownTokenReloadInterval)[id]
+	}
+
+	if record == nil || subtle.ConstantTimeCompare([]byte(record.Hash), []byte(hash(token))) != 1 ||
+		!self.now().Before(record.ExpiresAt) {
+		return nil, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	if !record.allows(method) {
+		return nil, errors.NewGenericResponse(http.StatusForbidden,
+			fmt.Sprintf("scopes of the personal access token do not allow %s requests", method))
+	}
+
+	return &api.AuthInfo{Impersonate: record.Owner, ImpersonateGroups: record.Groups}, nil
+}
+
+func (self *personalAccessTokenManager) records(syncInterval time.Duration) map[string]*record {
+	return self.store.Get(syncInterval).(map[string]*record)
+}
+
+func (self *personalAccessTokenManager) update(modify func(map[string]*record) error) error {
+	return self.store.Update(func(value interface{}) error {
+		return modify(value.(map[string]*record))
+	})
+}
+
+// Decode returns valid tokens stored in the secret. Expired tokens are dropped, so that they are deleted with the
+// next update.
+func (self *personalAccessTokenManager) Decode(data map[string][]byte) interface{} {
+	result := map[string]*record{}
+	for id, entry := range data {
+		record := new(record)
+		if err := json.Unmarshal(entry, record); err != nil {
+			log.Printf("Skipping invalid personal access token %s. Reason: %s", id, err)
+			continue
+		}
+
+		if self.now().Before(record.ExpiresAt) {
+			result[id] = record
+		}
+	}
+
+	return result
+}
+
+func (self *personalAccessTokenManager) Encode(value interface{}) (map[string][]byte, error) {
+	result := map[string][]byte{}
+	for id, record := range value.(map[string]*record) {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return nil, err
+		}
+
+		result[id] = data
+	}
+
+	return result, nil
+}
+
+func validate(spec authApi.PersonalAccessTokenSpec) error {
+	if len(spec.Name) == 0 || len(spec.Name) > maxNameLength {
+		return errors.NewBadRequest(fmt.Sprintf("name of the token is required and can have at most %d characters",
+			maxNameLength))
+	}
+
+	if len(spec.Scopes) == 0 {
+		return errors.NewBadRequest("at least one scope is required")
+	}
+
+	for _, scope := range spec.Scopes {
+		if scope != authApi.ReadScope && scope != authApi.WriteScope {
+			return errors.NewBadRequest(fmt.Sprintf("unknown scope %s", scope))
+		}
+	}
+
+	if spec.ExpirationSeconds < 0 || time.Duration(spec.ExpirationSeconds)*time.Second > MaxExpiration {
+		return errors.NewBadRequest(fmt.Sprintf("expiration can not be negative or longer than %s", MaxExpiration))
+	}
+
+	return nil
+}
+
+func ownedBy(records map[string]*record, owner string) []*record {
+	result := make([]*record, 0)
+	for _, record := range records {
+		if record.Owner == owner {
+			result = append(result, record)
+		}
+	}
+
+	return result
+}
+
+func hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(size int, encode func([]byte) string) (string, error) {
+	data := make([]byte, size)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+
+	return encode(data), nil
+}
+
+// NewPersonalAccessTokenManager creates PersonalAccessTokenManager that stores hashes of the tokens in the
+// AccessTokenHolderSecretName secret in the given namespace, so that they are shared by all replicas.
+func NewPersonalAccessTokenManager(kubernetes kubernetes.Interface, namespace string) authApi.PersonalAccessTokenManager {
+	manager := &personalAccessTokenManager{now: time.Now}
+	manager.store = store.NewSecretStore(kubernetes, namespace, authApi.AccessTokenHolderSecretName, manager)
+	return manager
+}
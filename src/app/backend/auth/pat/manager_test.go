@@ -0,0 +1,167 @@
+package pat
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+)
+
+const namespace = "kube-portal"
+
+var jane = authApi.Identity{Username: "jane", Groups: []string{"developers"}}
+
+func TestPersonalAccessTokenManager(t *testing.T) {
+	manager := NewPersonalAccessTokenManager(fake.NewSimpleClientset(), namespace)
+	readOnly, err := manager.Create(jane, authApi.PersonalAccessTokenSpec{
+		Name:   "ci",
+		Scopes: []authApi.PersonalAccessTokenScope{authApi.ReadScope},
+	})
+	if err != nil {
+		t.Fatalf("Create(): expected no error but got: %s", err)
+	}
+
+	if !strings.HasPrefix(readOnly.Token, authApi.PersonalAccessTokenPrefix) {
+		t.Errorf("Create(): expected token with %s prefix but got %s", authApi.PersonalAccessTokenPrefix,
+			readOnly.Token)
+	}
+
+	readWrite, _ := manager.Create(jane, authApi.PersonalAccessTokenSpec{
+		Name:              "deploy",
+		Scopes:            []authApi.PersonalAccessTokenScope{authApi.WriteScope},
+		ExpirationSeconds: 3600,
+	})
+
+	cases := []struct {
+		token, method string
+		expected      *api.AuthInfo
+		expectedCode  int32
+	}{
+		{readOnly.Token, http.MethodGet, &api.AuthInfo{Impersonate: "jane", ImpersonateGroups: jane.Groups}, 0},
+		{readOnly.Token, http.MethodPut, nil, http.StatusForbidden},
+		{readWrite.Token, http.MethodPut, &api.AuthInfo{Impersonate: "jane", ImpersonateGroups: jane.Groups}, 0},
+		{readOnly.Token + "x", http.MethodGet, nil, http.StatusUnauthorized},
+		{authApi.PersonalAccessTokenPrefix + "unknown_token", http.MethodGet, nil, http.StatusUnauthorized},
+	}
+
+	for _, c := range cases {
+		actual, err := manager.Resolve(c.token, c.method)
+		if c.expected == nil {
+			if statusErr, ok := err.(*k8sErrors.StatusError); !ok || statusErr.ErrStatus.Code != c.expectedCode {
+				t.Errorf("Resolve(%s, %s): expected error with status %d but got %v", c.token, c.method,
+					c.expectedCode, err)
+			}
+			continue
+		}
+
+		if err != nil || !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Resolve(%s, %s): expected %+v but got %+v, %v", c.token, c.method, c.expected, actual, err)
+		}
+	}
+
+	list, _ := manager.List("jane")
+	if len(list.Tokens) != 2 || list.Tokens[0].Name != "ci" || list.Tokens[1].Name != "deploy" {
+		t.Errorf("List(): expected ci and deploy tokens but got %+v", list.Tokens)
+	}
+
+	if list, _ = manager.List("john"); len(list.Tokens) != 0 {
+		t.Errorf("List(): expected no tokens of other user but got %+v", list.Tokens)
+	}
+
+	if err = manager.Revoke("john", readOnly.ID); !errors.IsNotFound(err) {
+		t.Errorf("Revoke(): expected tokens of other users to be not found but got %v", err)
+	}
+
+	if err = manager.Revoke("jane", readOnly.ID); err != nil {
+		t.Fatalf("Revoke(): expected no error but got: %s", err)
+	}
+
+	if _, err = manager.Resolve(readOnly.Token, http.MethodGet); !errors.IsUnauthorized(err) {
+		t.Errorf("Resolve(): expected revoked token to be rejected but got %v", err)
+	}
+}
+
+func TestPersonalAccessTokenManagerExpiredToken(t *testing.T) {
+	manager := NewPersonalAccessTokenManager(fake.NewSimpleClientset(), namespace).(*personalAccessTokenManager)
+	now := time.Now()
+	manager.now = func() time.Time { return now }
+
+	token, _ := manager.Create(jane, authApi.PersonalAccessTokenSpec{
+		Name:              "ci",
+		Scopes:            []authApi.PersonalAccessTokenScope{authApi.ReadScope},
+		ExpirationSeconds: 60,
+	})
+
+	now = now.Add(time.Minute)
+	if _, err := manager.Resolve(token.Token, http.MethodGet); !errors.IsUnauthorized(err) {
+		t.Errorf("Resolve(): expected expired token to be rejected but got %v", err)
+	}
+
+	if list, _ := manager.List("jane"); len(list.Tokens) != 0 {
+		t.Errorf("List(): expected expired tokens to be hidden but got %+v", list.Tokens)
+	}
+}
+
+func TestPersonalAccessTokenManagerRevokeOwner(t *testing.T) {
+	manager := NewPersonalAccessTokenManager(fake.NewSimpleClientset(), namespace)
+	spec := authApi.PersonalAccessTokenSpec{Name: "ci", Scopes: []authApi.PersonalAccessTokenScope{authApi.ReadScope}}
+	first, _ := manager.Create(jane, spec)
+	second, _ := manager.Create(jane, spec)
+	other, _ := manager.Create(authApi.Identity{Username: "john"}, spec)
+
+	if err := manager.RevokeOwner("jane"); err != nil {
+		t.Fatalf("RevokeOwner(): expected no error but got: %s", err)
+	}
+
+	for _, token := range []string{first.Token, second.Token} {
+		if _, err := manager.Resolve(token, http.MethodGet); !errors.IsUnauthorized(err) {
+			t.Errorf("Resolve(): expected tokens of revoked owner to be rejected but got %v", err)
+		}
+	}
+
+	if _, err := manager.Resolve(other.Token, http.MethodGet); err != nil {
+		t.Errorf("Resolve(): expected tokens of other users to stay valid but got: %s", err)
+	}
+}
+
+func TestPersonalAccessTokenManagerSharedTokens(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	first := NewPersonalAccessTokenManager(fakeClient, namespace)
+	second := NewPersonalAccessTokenManager(fakeClient, namespace)
+
+	token, _ := first.Create(jane, authApi.PersonalAccessTokenSpec{
+		Name:   "ci",
+		Scopes: []authApi.PersonalAccessTokenScope{authApi.ReadScope},
+	})
+	if _, err := second.Resolve(token.Token, http.MethodGet); err != nil {
+		t.Errorf("Resolve(): expected tokens to be shared by replicas but got: %s", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		spec  authApi.PersonalAccessTokenSpec
+		valid bool
+	}{
+		{authApi.PersonalAccessTokenSpec{Name: "ci", Scopes: []authApi.PersonalAccessTokenScope{"read"}}, true},
+		{authApi.PersonalAccessTokenSpec{Scopes: []authApi.PersonalAccessTokenScope{"read"}}, false},
+		{authApi.PersonalAccessTokenSpec{Name: "ci"}, false},
+		{authApi.PersonalAccessTokenSpec{Name: "ci", Scopes: []authApi.PersonalAccessTokenScope{"admin"}}, false},
+		{authApi.PersonalAccessTokenSpec{Name: "ci", Scopes: []authApi.PersonalAccessTokenScope{"write"},
+			ExpirationSeconds: int64(MaxExpiration/time.Second) + 1}, false},
+	}
+
+	for _, c := range cases {
+		if err := validate(c.spec); (err == nil) != c.valid {
+			t.Errorf("validate(%+v): expected valid to be %t but got %v", c.spec, c.valid, err)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package store
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+)
+
+// Number of attempts to update the secret when it is modified by other replicas at the same time.
+const updateRetries = 5
+
+// Codec converts value kept by SecretStore from and to data of the secret.
+type Codec interface {
+	// Decode returns value stored in the data. Data is nil when the secret does not exist yet. Returned value must
+	// not share memory with the data.
+	Decode(data map[string][]byte) interface{}
+	Encode(value interface{}) (map[string][]byte, error)
+}
+
+// SecretStore keeps value decoded from a secret in memory, so that it is shared by all replicas. Value returned by
+// Get must not be modified, changes are made only with Update.
+type SecretStore struct {
+	mux        sync.Mutex
+	value      interface{}
+	lastSynced time.Time
+	kubernetes kubernetes.Interface
+	namespace  string
+	name       string
+	codec      Codec
+}
+
+// Get returns the value, reloaded from the secret first when it has not been reloaded for the given interval.
+// Value of the store without kubernetes client is never reloaded.
+func (self *SecretStore) Get(syncInterval time.Duration) interface{} {
+	self.sync(syncInterval)
+
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	return self.value
+}
+
+// Update applies modify to a copy of the current value and stores the result. Update of the secret is retried
+// with the reloaded value when other replica modified it in the meantime.
+func (self *SecretStore) Update(modify func(value interface{}) error) error {
+	if self.kubernetes == nil {
+		self.mux.Lock()
+		defer self.mux.Unlock()
+
+		data, err := self.codec.Encode(self.value)
+		if err != nil {
+			return err
+		}
+
+		value := self.codec.Decode(data)
+		if err = modify(value); err != nil {
+			return err
+		}
+
+		self.value = value
+		return nil
+	}
+
+	var err error
+	for i := 0; i < updateRetries; i++ {
+		if err = self.updateSecret(modify); !errors.IsConflict(err) && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+func (self *SecretStore) updateSecret(modify func(value interface{}) error) error {
+	secret, err := self.getSecret()
+	notFound := errors.IsNotFound(err)
+	if err != nil && !notFound {
+		return err
+	}
+
+	if notFound {
+		secret = &v1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: self.name, Namespace: self.namespace}}
+	}
+
+	value := self.codec.Decode(secret.Data)
+	if err = modify(value); err != nil {
+		return err
+	}
+
+	if secret.Data, err = self.codec.Encode(value); err != nil {
+		return err
+	}
+
+	secrets := self.kubernetes.CoreV1().Secrets(self.namespace)
+	if notFound {
+		_, err = secrets.Create(context.TODO(), secret, metaV1.CreateOptions{})
+	} else {
+		_, err = secrets.Update(context.TODO(), secret, metaV1.UpdateOptions{})
+	}
+
+	if err != nil {
+		return err
+	}
+
+	self.set(value)
+	return nil
+}
+
+func (self *SecretStore) sync(interval time.Duration) {
+	self.mux.Lock()
+	if self.kubernetes == nil || time.Since(self.lastSynced) < interval {
+		self.mux.Unlock()
+		return
+	}
+	self.lastSynced = time.Now()
+	self.mux.Unlock()
+
+	secret, err := self.getSecret()
+	if errors.IsNotFound(err) {
+		self.set(self.codec.Decode(nil))
+		return
+	}
+
+	if err != nil {
+		log.Printf("Could not reload %s secret. Reason: %s", self.name, err)
+		return
+	}
+
+	self.set(self.codec.Decode(secret.Data))
+}
+
+func (self *SecretStore) set(value interface{}) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	self.value = value
+	self.lastSynced = time.Now()
+}
+
+func (self *SecretStore) getSecret() (*v1.Secret, error) {
+	return self.kubernetes.CoreV1().Secrets(self.namespace).Get(context.TODO(), self.name, metaV1.GetOptions{})
+}
+
+// NewSecretStore creates SecretStore of the secret with the given name and namespace. Store without kubernetes
+// client keeps the value in memory of a single replica.
+func NewSecretStore(kubernetes kubernetes.Interface, namespace, name string, codec Codec) *SecretStore {
+	return &SecretStore{
+		value:      codec.Decode(nil),
+		kubernetes: kubernetes,
+		namespace:  namespace,
+		name:       name,
+		codec:      codec,
+	}
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+)
+
+// namesCodec stores names as keys of the secret.
+type namesCodec struct{}
+
+func (self namesCodec) Decode(data map[string][]byte) interface{} {
+	result := map[string]bool{}
+	for name := range data {
+		result[name] = true
+	}
+
+	return result
+}
+
+func (self namesCodec) Encode(value interface{}) (map[string][]byte, error) {
+	result := map[string][]byte{}
+	for name := range value.(map[string]bool) {
+		result[name] = []byte{}
+	}
+
+	return result, nil
+}
+
+func add(name string) func(interface{}) error {
+	return func(value interface{}) error {
+		value.(map[string]bool)[name] = true
+		return nil
+	}
+}
+
+func TestSecretStoreInMemory(t *testing.T) {
+	store := NewSecretStore(nil, "", "names", namesCodec{})
+	if err := store.Update(add("jane")); err != nil {
+		t.Fatalf("Update(): expected no error but got: %s", err)
+	}
+
+	before := store.Get(time.Second)
+	if err := store.Update(add("john")); err != nil {
+		t.Fatalf("Update(): expected no error but got: %s", err)
+	}
+
+	if expected := map[string]bool{"jane": true}; !reflect.DeepEqual(before, expected) {
+		t.Errorf("Update(): expected returned values to stay unchanged but got %v", before)
+	}
+
+	if expected := map[string]bool{"jane": true, "john": true}; !reflect.DeepEqual(store.Get(time.Second), expected) {
+		t.Errorf("Get(): expected %v but got %v", expected, store.Get(time.Second))
+	}
+}
+
+func TestSecretStoreShared(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	first := NewSecretStore(fakeClient, "kube-portal", "names", namesCodec{})
+	second := NewSecretStore(fakeClient, "kube-portal", "names", namesCodec{})
+
+	if err := first.Update(add("jane")); err != nil {
+		t.Fatalf("Update(): expected no error but got: %s", err)
+	}
+
+	// Update conflicting with other replica is retried with the reloaded value.
+	conflicts := 1
+	fakeClient.PrependReactor("update", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if conflicts > 0 {
+			conflicts--
+			return true, nil, k8sErrors.NewConflict(schema.GroupResource{Resource: "secrets"}, "names", nil)
+		}
+
+		return false, nil, nil
+	})
+
+	if err := second.Update(add("john")); err != nil {
+		t.Fatalf("Update(): expected conflict to be retried but got: %s", err)
+	}
+
+	expected := map[string]bool{"jane": true, "john": true}
+	if actual := first.Get(0); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Get(): expected value to be shared by replicas but got %v", actual)
+	}
+
+	conflicts = updateRetries
+	if err := first.Update(add("anna")); !errors.IsConflict(err) || !strings.Contains(err.Error(), "names") {
+		t.Errorf("Update(): expected conflict after %d attempts but got: %v", updateRetries, err)
+	}
+}
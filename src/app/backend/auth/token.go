@@ -0,0 +1,23 @@
+package auth
+
+import (
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+)
+
+type tokenAuthenticator struct {
+	token string
+}
+
+func (self *tokenAuthenticator) GetAuthInfo() (api.AuthInfo, error) {
+	return api.AuthInfo{
+		Token: self.token,
+	}, nil
+}
+
+func NewTokenAuthenticator(spec *authApi.LoginSpec) authApi.Authenticator {
+	return &tokenAuthenticator{
+		token: spec.Token,
+	}
+}
@@ -0,0 +1,16 @@
+package api
+
+import "crypto/tls"
+
+const (
+	// CertificateSecretData and KeySecretData are keys of the PEM encoded certificate and private key stored in
+	// the authApi.CertificateHolderSecretName secret.
+	CertificateSecretData = "tls.crt"
+	KeySecretData         = "tls.key"
+)
+
+// CertificateManager provides the certificate used to serve HTTPS.
+type CertificateManager interface {
+	// Certificate returns the certificate given by the user or, if there is none, the one generated by the portal.
+	Certificate() (tls.Certificate, error)
+}
@@ -0,0 +1,196 @@
+package cert
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log"
+	"math/big"
+	"net"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+	certApi "github.com/donghoon-khan/kubeportal/src/app/backend/cert/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+)
+
+const (
+	certificateValidity = 365 * 24 * time.Hour
+	// Stored certificate that expires sooner than that is replaced on startup.
+	minRemainingValidity = 7 * 24 * time.Hour
+	commonName           = "kube-portal"
+)
+
+// certificateManager reads certificate from the given files. If there are none, self-signed certificate is
+// generated and persisted in the CertificateHolderSecretName secret, so that it survives restarts and is shared
+// by every replica.
+type certificateManager struct {
+	certFile   string
+	keyFile    string
+	kubernetes kubernetes.Interface
+	namespace  string
+}
+
+func (self *certificateManager) Certificate() (tls.Certificate, error) {
+	if len(self.certFile) > 0 && len(self.keyFile) > 0 {
+		return tls.LoadX509KeyPair(self.certFile, self.keyFile)
+	}
+
+	return self.storedCertificate()
+}
+
+func (self *certificateManager) storedCertificate() (tls.Certificate, error) {
+	secret, err := self.kubernetes.CoreV1().Secrets(self.namespace).Get(context.TODO(),
+		authApi.CertificateHolderSecretName, metaV1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return self.create()
+	}
+
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	certificate, err := parseCertificate(secret.Data[certApi.CertificateSecretData],
+		secret.Data[certApi.KeySecretData])
+	if err == nil {
+		log.Printf("Using certificate stored in %s secret", authApi.CertificateHolderSecretName)
+		return certificate, nil
+	}
+
+	log.Printf("Certificate stored in %s secret can not be used, generating a new one. Reason: %s",
+		authApi.CertificateHolderSecretName, err)
+	certPEM, keyPEM, err := GenerateCertificate()
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+	secret.Data[certApi.CertificateSecretData] = certPEM
+	secret.Data[certApi.KeySecretData] = keyPEM
+	_, err = self.kubernetes.CoreV1().Secrets(self.namespace).Update(context.TODO(), secret, metaV1.UpdateOptions{})
+	if errors.IsConflict(err) {
+		// Other replica has been faster, use its certificate.
+		return self.storedCertificate()
+	}
+
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
+
+func (self *certificateManager) create() (tls.Certificate, error) {
+	certPEM, keyPEM, err := GenerateCertificate()
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      authApi.CertificateHolderSecretName,
+			Namespace: self.namespace,
+		},
+		Data: map[string][]byte{
+			certApi.CertificateSecretData: certPEM,
+			certApi.KeySecretData:         keyPEM,
+		},
+	}
+
+	_, err = self.kubernetes.CoreV1().Secrets(self.namespace).Create(context.TODO(), secret, metaV1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		// Other replica has been faster, use its certificate.
+		return self.storedCertificate()
+	}
+
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	log.Printf("Storing generated certificate in %s secret", authApi.CertificateHolderSecretName)
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
+
+// parseCertificate parses PEM encoded certificate and key. Certificates that expire soon are rejected, so that
+// they are replaced before browsers start to refuse them.
+func parseCertificate(certPEM, keyPEM []byte) (tls.Certificate, error) {
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	if time.Now().Add(minRemainingValidity).After(leaf.NotAfter) {
+		return tls.Certificate{}, errors.NewInvalid("certificate expires at " + leaf.NotAfter.String())
+	}
+
+	certificate.Leaf = leaf
+	return certificate, nil
+}
+
+// GenerateCertificate returns PEM encoded self-signed certificate and its ECDSA private key. Certificate is valid
+// for localhost, other hosts are reached through the certificate given by the user.
+func GenerateCertificate() ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(certificateValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost", commonName},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+// NewCertificateManager creates CertificateManager that reads certificate from certFile and keyFile or, if they
+// are not given, from the CertificateHolderSecretName secret in the given namespace.
+func NewCertificateManager(certFile, keyFile string, kubernetes kubernetes.Interface,
+	namespace string) certApi.CertificateManager {
+	return &certificateManager{
+		certFile:   certFile,
+		keyFile:    keyFile,
+		kubernetes: kubernetes,
+		namespace:  namespace,
+	}
+}
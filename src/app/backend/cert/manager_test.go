@@ -0,0 +1,108 @@
+package cert_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/cert"
+	certApi "github.com/donghoon-khan/kubeportal/src/app/backend/cert/api"
+)
+
+const namespace = "kube-portal"
+
+func TestCertificateManager(t *testing.T) {
+	cases := []struct {
+		existing *v1.Secret
+	}{
+		{nil},
+		{&v1.Secret{ObjectMeta: metaV1.ObjectMeta{Name: authApi.CertificateHolderSecretName, Namespace: namespace}}},
+		{&v1.Secret{
+			ObjectMeta: metaV1.ObjectMeta{Name: authApi.CertificateHolderSecretName, Namespace: namespace},
+			Data:       map[string][]byte{certApi.CertificateSecretData: []byte("invalid")},
+		}},
+	}
+
+	for _, c := range cases {
+		fakeClient := fake.NewSimpleClientset()
+		if c.existing != nil {
+			fakeClient = fake.NewSimpleClientset(c.existing)
+		}
+
+		first, err := cert.NewCertificateManager("", "", fakeClient, namespace).Certificate()
+		if err != nil {
+			t.Fatalf("Certificate(): Expected no error but got: %s", err)
+		}
+
+		secret, err := fakeClient.CoreV1().Secrets(namespace).Get(context.TODO(),
+			authApi.CertificateHolderSecretName, metaV1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Expected certificate to be stored in secret but got: %s", err)
+		}
+
+		if !bytes.Contains(secret.Data[certApi.CertificateSecretData], []byte("CERTIFICATE")) {
+			t.Errorf("Expected secret to contain PEM encoded certificate but got: %s",
+				secret.Data[certApi.CertificateSecretData])
+		}
+
+		// Restarted or other replica should reuse the stored certificate.
+		second, err := cert.NewCertificateManager("", "", fakeClient, namespace).Certificate()
+		if err != nil {
+			t.Fatalf("Certificate(): Expected no error but got: %s", err)
+		}
+
+		if !reflect.DeepEqual(first.Certificate, second.Certificate) {
+			t.Error("Certificate(): Expected stored certificate to be reused")
+		}
+	}
+}
+
+func TestNewServerTLSConfig(t *testing.T) {
+	certPEM, _, err := cert.GenerateCertificate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caFile, err := ioutil.TempFile("", "ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(caFile.Name())
+	caFile.Write(certPEM)
+	caFile.Close()
+
+	cases := []struct {
+		clientCAFile      string
+		requireClientCert bool
+		expected          tls.ClientAuthType
+		expectedErr       bool
+	}{
+		{"", false, tls.NoClientCert, false},
+		{"", true, tls.NoClientCert, true},
+		{"/non/existing/ca.crt", false, tls.NoClientCert, true},
+		{caFile.Name(), false, tls.VerifyClientCertIfGiven, false},
+		{caFile.Name(), true, tls.RequireAndVerifyClientCert, false},
+	}
+
+	for _, c := range cases {
+		config, err := cert.NewServerTLSConfig(tls.Certificate{}, c.clientCAFile, c.requireClientCert)
+		if (err != nil) != c.expectedErr {
+			t.Fatalf("NewServerTLSConfig(%s, %v): Expected error to be %v but got: %v", c.clientCAFile,
+				c.requireClientCert, c.expectedErr, err)
+		}
+
+		if err == nil && config.ClientAuth != c.expected {
+			t.Errorf("NewServerTLSConfig(%s, %v): Expected client auth %v but got %v", c.clientCAFile,
+				c.requireClientCert, c.expected, config.ClientAuth)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package cert
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+)
+
+// NewServerTLSConfig creates TLS config serving the certificate. When clientCAFile is given, client certificates
+// are verified against its CAs. With requireClientCert clients without a valid certificate are refused.
+func NewServerTLSConfig(certificate tls.Certificate, clientCAFile string, requireClientCert bool) (*tls.Config,
+	error) {
+	config := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if len(clientCAFile) == 0 {
+		if requireClientCert {
+			return nil, errors.NewInvalid("client CA file is required to verify client certificates")
+		}
+
+		return config, nil
+	}
+
+	data, err := ioutil.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	config.ClientCAs = x509.NewCertPool()
+	if !config.ClientCAs.AppendCertsFromPEM(data) {
+		return nil, errors.NewInvalid("no certificates found in client CA file " + clientCAFile)
+	}
+
+	config.ClientAuth = tls.VerifyClientCertIfGiven
+	if requireClientCert {
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return config, nil
+}
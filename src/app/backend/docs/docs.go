@@ -10,16 +10,25 @@ import (
 )
 
 const (
+	APIResourceDocsTag           = "APIResource"
 	AuthenticationDocsTag        = "Authentication"
+	CanIDocsTag                  = "CanI"
+	ClusterDocsTag               = "Cluster"
+	PersonalAccessTokenDocsTag   = "PersonalAccessToken"
+	StatsDocsTag                 = "Stats"
 	IntegrationDocsTag           = "Integration"
 	ClusterRoleBindingDocsTag    = "ClusterRoleBinding"
 	ClusterRoleDocsTag           = "ClusterRole"
 	ConfigMapDocsTag             = "ConfigMap"
 	CronJobDocsTag               = "CronJob"
+	CsrfTokenDocsTag             = "CsrfToken"
 	IngressDocsTag               = "Ingress"
+	JobDocsTag                   = "Job"
+	LogDocsTag                   = "Log"
 	NodeDocsTag                  = "Node"
 	PersistentVolumeClaimDocsTag = "PersistentVolumeClaim"
 	PodDocsTag                   = "Pod"
+	RawDocsTag                   = "Raw"
 	SecretDocsTag                = "Sceret"
 	ServiceDocsTag               = "Service"
 	ServiceAccountDocsTag        = "ServiceAccount"
@@ -66,6 +75,47 @@ func enrichSwaggerObject(swo *spec.Swagger) {
 					"<br/>Ref: https://github.com/kubernetes-sigs/metrics-server or https://github.com/kubernetes-retired/heapster",
 			},
 		},
+		{
+			TagProps: spec.TagProps{
+				Name: CsrfTokenDocsTag,
+				Description: "Every mutating request must carry a CSRF token generated for its action in the X-CSRF-TOKEN header." +
+					" The action is the first path segment of the request after the API root, e.g. login or cronjob.",
+			},
+		},
+		{
+			TagProps: spec.TagProps{
+				Name: CanIDocsTag,
+				Description: "Checks whether the user is allowed to perform an action, so that actions the user is not" +
+					" allowed to perform can be hidden or disabled. Checks are done with SelfSubjectAccessReview.",
+			},
+		},
+		{
+			TagProps: spec.TagProps{
+				Name: ClusterDocsTag,
+				Description: "Portal can serve several clusters. Routes under /api/v1/cluster/{cluster} work against" +
+					" the given cluster, other routes work against the default one.",
+			},
+		},
+		{
+			TagProps: spec.TagProps{
+				Name: APIResourceDocsTag,
+				Description: "Resources found by the discovery of the cluster, including custom resources. Objects of any" +
+					" of them can be browsed, core resources are served under the core group.",
+			},
+		},
+		{
+			TagProps: spec.TagProps{
+				Name: PersonalAccessTokenDocsTag,
+				Description: "Personal access tokens can be used by scripts instead of logging in. They are passed in the" +
+					" Authorization header as bearer tokens and act as the user that created them, limited by their scopes.",
+			},
+		},
+		{
+			TagProps: spec.TagProps{
+				Name:        StatsDocsTag,
+				Description: "Counters of the portal's internals, e.g. of the cache of clients created with users' credentials.",
+			},
+		},
 		{
 			TagProps: spec.TagProps{
 				Name: ClusterRoleBindingDocsTag,
@@ -102,6 +152,20 @@ func enrichSwaggerObject(swo *spec.Swagger) {
 					"<br/>Ref: https://kubernetes.io/docs/concepts/services-networking/ingress/",
 			},
 		},
+		{
+			TagProps: spec.TagProps{
+				Name: JobDocsTag,
+				Description: "A Job creates one or more Pods and will continue to retry execution of the Pods until a specified number of them successfully terminate. Finished Jobs can be re-run as new Jobs with the same spec." +
+					"<br/>Ref: https://kubernetes.io/docs/concepts/workloads/controllers/job/",
+			},
+		},
+		{
+			TagProps: spec.TagProps{
+				Name: LogDocsTag,
+				Description: "Logs of the containers, read page by page relative to a reference line, followed as Server-Sent Events or downloaded as a whole file." +
+					"<br/>Ref: https://kubernetes.io/docs/concepts/cluster-administration/logging/",
+			},
+		},
 		{
 			TagProps: spec.TagProps{
 				Name: NodeDocsTag,
@@ -123,6 +187,13 @@ func enrichSwaggerObject(swo *spec.Swagger) {
 					"<br/>Ref: https://kubernetes.io/docs/concepts/workloads/pods/",
 			},
 		},
+		{
+			TagProps: spec.TagProps{
+				Name: RawDocsTag,
+				Description: "Raw routes read, replace and delete objects of any kind known to the portal exactly as" +
+					" they are stored by the API server. Cluster scoped kinds are served by the routes without namespace.",
+			},
+		},
 		{
 			TagProps: spec.TagProps{
 				Name: SecretDocsTag,
@@ -30,6 +30,28 @@ func NewTokenExpired(reason string) *errors.StatusError {
 	}
 }
 
+func NewTooManyRequests(reason string) *errors.StatusError {
+	return &errors.StatusError{
+		ErrStatus: metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusTooManyRequests,
+			Reason:  metav1.StatusReasonTooManyRequests,
+			Message: reason,
+		},
+	}
+}
+
+func NewRequestEntityTooLarge(reason string) *errors.StatusError {
+	return &errors.StatusError{
+		ErrStatus: metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusRequestEntityTooLarge,
+			Reason:  metav1.StatusReasonRequestEntityTooLarge,
+			Message: reason,
+		},
+	}
+}
+
 func NewBadRequest(reason string) *errors.StatusError {
 	return errors.NewBadRequest(reason)
 }
@@ -150,3 +172,11 @@ func IsAlreadyExists(err error) bool {
 func IsUnauthorized(err error) bool {
 	return errors.IsUnauthorized(err)
 }
+
+func IsNotFound(err error) bool {
+	return errors.IsNotFound(err)
+}
+
+func IsConflict(err error) bool {
+	return errors.IsConflict(err)
+}
@@ -97,7 +97,8 @@ func HandleHttpError(err error) int {
 	if err == nil {
 		return http.StatusInternalServerError
 	}
-	if err.Error() == MsgTokenExpiredError || err.Error() == MsgLoginUnauthorizedError || err.Error() == MsgEncryptionKeyChanged {
+	if err.Error() == MsgTokenExpiredError || err.Error() == MsgLoginUnauthorizedError ||
+		err.Error() == MsgEncryptionKeyChanged || err.Error() == MsgTokenRevokedError {
 		return http.StatusUnauthorized
 	}
 	return http.StatusInternalServerError
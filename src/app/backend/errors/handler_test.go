@@ -40,6 +40,10 @@ func TestHandlerHttpError(t *testing.T) {
 			errors.NewInvalid(errors.MsgDashboardExclusiveResourceError),
 			500,
 		},
+		{
+			errors.NewInvalid(errors.MsgTokenRevokedError),
+			401,
+		},
 		{
 			errors.NewInvalid(errors.MsgTokenExpiredError),
 			401,
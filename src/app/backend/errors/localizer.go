@@ -13,6 +13,9 @@ const (
 	MsgEncryptionKeyChanged            = "MSG_ENCRYPTION_KEY_CHANGED"
 	MsgDashboardExclusiveResourceError = "MSG_DASHBOARD_EXCLUSIVE_RESOURCE_ERROR"
 	MsgTokenExpiredError               = "MSG_TOKEN_EXPIRED_ERROR"
+	MsgCsrfValidationError             = "MSG_CSRF_VALIDATION_ERROR"
+	MsgTokenRevokedError               = "MSG_TOKEN_REVOKED_ERROR"
+	MsgLoginTooManyAttemptsError       = "MSG_LOGIN_TOO_MANY_ATTEMPTS_ERROR"
 )
 
 var partialsToErrorsMap = map[string]string{
@@ -5,9 +5,12 @@ import (
 
 	"github.com/emicklei/go-restful/v3"
 
+	"github.com/donghoon-khan/kubeportal/src/app/backend/args"
 	"github.com/donghoon-khan/kubeportal/src/app/backend/auth"
 	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/auth/pat"
 	"github.com/donghoon-khan/kubeportal/src/app/backend/integration"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes"
 	k8sApi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
 	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/common"
 )
@@ -25,24 +28,96 @@ type APIHandler struct {
 func CreateHttpApiHandler(
 	iManager integration.IntegrationManager,
 	kManager k8sApi.KubernetesManager,
-	authManager authApi.AuthManager) (*restful.Container, error) {
+	authManager authApi.AuthManager,
+	patManager authApi.PersonalAccessTokenManager) (*restful.Container, error) {
 
 	apiHandler := APIHandler{iManager: iManager, kManager: kManager}
 	wsContainer := restful.NewContainer()
 	wsContainer.EnableContentEncoding(true)
 
 	k8sWs := new(restful.WebService)
+	k8sWs.Path("/api/v1/kubernetes").
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON)
+	kubernetesParams(k8sWs)
 	InstallFilters(k8sWs, kManager)
+	apiHandler.installKubernetes(k8sWs, "")
+	wsContainer.Add(k8sWs)
 
-	k8sWs.Path("/api/v1/kubernetes").
+	clusterWs := new(restful.WebService)
+	clusterWs.Path("/api/v1/cluster").
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON)
+	kubernetesParams(clusterWs)
+	clusterWs.Param(clusterWs.PathParameter(k8sApi.ClusterPathParameter,
+		"Name of the cluster serving the request, not used by the list of clusters").Required(true))
+	apiHandler.installCluster(clusterWs)
+	wsContainer.Add(clusterWs)
+
+	integrationHandler := integration.NewIntegrationHandler(iManager)
+	integrationWs := new(restful.WebService)
+	integrationWs.Path("/api/v1/integration").
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON)
+
+	integrationHandler.Install(integrationWs)
+	wsContainer.Add(integrationWs)
+
+	authHandler := auth.NewAuthHandler(authManager, kManager)
+	authWs := new(restful.WebService)
+	authWs.Path("/api/v1/authentication").
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON)
+	InstallFilters(authWs, kManager)
+	authWs.Filter(loginRateLimitFilter(newLoginLimiter(loginLimits{
+		maxAttemptsPerUser: args.Holder.GetLoginMaxAttemptsPerUser(),
+		maxAttemptsPerIP:   args.Holder.GetLoginMaxAttemptsPerIp(),
+		backoff:            args.Holder.GetLoginBackoff(),
+		lockout:            args.Holder.GetLoginLockout(),
+	}), authWs.RootPath()))
+	authHandler.Install(authWs)
+	pat.NewPersonalAccessTokenHandler(patManager, kManager).Install(authWs)
+	wsContainer.Add(authWs)
+
+	csrfWs := new(restful.WebService)
+	csrfWs.Path("/api/v1/csrftoken").
+		Consumes(restful.MIME_JSON).
+		Produces(restful.MIME_JSON)
+	apiHandler.installCsrfToken(csrfWs)
+	wsContainer.Add(csrfWs)
+
+	// Access reviews do not modify anything, so the batch request does not require CSRF token.
+	caniWs := new(restful.WebService)
+	caniWs.Path("/api/v1/cani").
 		Consumes(restful.MIME_JSON).
-		Produces(restful.MIME_JSON).
+		Produces(restful.MIME_JSON)
+	apiHandler.installCanI(caniWs, "")
+	wsContainer.Add(caniWs)
+
+	statsWs := new(restful.WebService)
+	statsWs.Path("/api/v1/stats").
+		Produces(restful.MIME_JSON)
+	apiHandler.installStats(statsWs)
+	wsContainer.Add(statsWs)
+
+	return wsContainer, nil
+}
+
+// kubernetesParams documents parameters shared by routes of the Kubernetes resources.
+func kubernetesParams(k8sWs *restful.WebService) {
+	k8sWs.
 		Param(
 			k8sWs.QueryParameter("itemPerPage",
 				"The number of items per page can be configured adding a query parameter named itemsPerPage"+
 					" `e.g. itemPerPage=10`").
 				DataType("int")).
-		Param(k8sWs.HeaderParameter("token", "token")).
+		Param(k8sWs.HeaderParameter(kubernetes.JWETokenHeader, "JWE token issued by the Authentication API")).
+		Param(k8sWs.HeaderParameter(kubernetes.AuthorizationHeader,
+			"Kubernetes bearer token `e.g. Authorization: Bearer <token>`")).
+		Param(k8sWs.HeaderParameter(kubernetes.ImpersonateUserHeader,
+			"User to impersonate, the caller must be allowed to impersonate it")).
+		Param(k8sWs.HeaderParameter(kubernetes.ImpersonateGroupHeader,
+			"Group to impersonate, can be repeated. Requires "+kubernetes.ImpersonateUserHeader)).
 		Param(
 			k8sWs.QueryParameter("page", "The number of page `e.g. page=1`").DataType("int")).
 		Param(
@@ -57,38 +132,24 @@ func CreateHttpApiHandler(
 					"{name or creationTimestamp or namespace or statusor type}"+
 					" `e.g. filterBy=namespace,kube-system`").
 				DataType("Collection of string(csv)"))
+}
 
-	apiHandler.installClusterRole(k8sWs)
-	apiHandler.installClusterRoleBinding(k8sWs)
-	apiHandler.installConfigMap(k8sWs)
-	apiHandler.installCronJob(k8sWs)
-	apiHandler.installIngress(k8sWs)
-	apiHandler.installPersistentVolumeClaim(k8sWs)
-	apiHandler.installPod(k8sWs)
-	apiHandler.installNode(k8sWs)
-	apiHandler.installSecret(k8sWs)
-	apiHandler.installService(k8sWs)
-	apiHandler.installServiceAccount(k8sWs)
-	wsContainer.Add(k8sWs)
-
-	integrationHandler := integration.NewIntegrationHandler(iManager)
-	integrationWs := new(restful.WebService)
-	integrationWs.Path("/api/v1/integration").
-		Consumes(restful.MIME_JSON).
-		Produces(restful.MIME_JSON)
-
-	integrationHandler.Install(integrationWs)
-	wsContainer.Add(integrationWs)
-
-	authHandler := auth.NewAuthHandler(authManager)
-	authWs := new(restful.WebService)
-	authWs.Path("/api/v1/authentication").
-		Consumes(restful.MIME_JSON).
-		Produces(restful.MIME_JSON)
-	authHandler.Install(authWs)
-	wsContainer.Add(authWs)
-
-	return wsContainer, nil
+func (apiHandler *APIHandler) installKubernetes(k8sWs *restful.WebService, prefix string) {
+	apiHandler.installAPIResource(k8sWs, prefix)
+	apiHandler.installClusterRole(k8sWs, prefix)
+	apiHandler.installClusterRoleBinding(k8sWs, prefix)
+	apiHandler.installConfigMap(k8sWs, prefix)
+	apiHandler.installCronJob(k8sWs, prefix)
+	apiHandler.installIngress(k8sWs, prefix)
+	apiHandler.installJob(k8sWs, prefix)
+	apiHandler.installLog(k8sWs, prefix)
+	apiHandler.installPersistentVolumeClaim(k8sWs, prefix)
+	apiHandler.installPod(k8sWs, prefix)
+	apiHandler.installRaw(k8sWs, prefix)
+	apiHandler.installNode(k8sWs, prefix)
+	apiHandler.installSecret(k8sWs, prefix)
+	apiHandler.installService(k8sWs, prefix)
+	apiHandler.installServiceAccount(k8sWs, prefix)
 }
 
 func parseNamespacePathParameter(request *restful.Request) *common.NamespaceQuery {
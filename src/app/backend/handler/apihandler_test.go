@@ -3,20 +3,35 @@ package handler
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"os"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/emicklei/go-restful/v3"
+	"golang.org/x/net/xsrftoken"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
 
 	"github.com/donghoon-khan/kubeportal/src/app/backend/args"
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/auth/jwe"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes"
+	k8sApi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
 )
 
 /*func TestCreateHttpApiHandler(t *testing.T) {
 
 	kManager := kubernetes.NewKubernetesManager("", "http://localhost:8080")
-	aManager := auth.NewAuthManager(kManager, authApi.AuthenticationModes{}, true)
+	aManager := auth.NewAuthManager(kManager, nil, nil, nil, authApi.AuthenticationModes{}, true)
 
 	_, err := CreateHttpApiHandler(kManager, aManager)
 	if err != nil {
@@ -121,3 +136,514 @@ func TestFormatRequestLog(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateXSRFFilter(t *testing.T) {
+	csrfKey := "test-key"
+	cases := []struct {
+		method, token string
+		expected      int
+	}{
+		{http.MethodGet, "", http.StatusOK},
+		{http.MethodPut, "", http.StatusForbidden},
+		{http.MethodPut, xsrftoken.Generate(csrfKey, csrfTokenUser, "login"), http.StatusForbidden},
+		{http.MethodPut, xsrftoken.Generate("other-key", csrfTokenUser, "cronjob"), http.StatusForbidden},
+		{http.MethodPut, xsrftoken.Generate(csrfKey, csrfTokenUser, "cronjob"), http.StatusOK},
+	}
+
+	for _, c := range cases {
+		ws := new(restful.WebService)
+		ws.Path("/api/v1/kubernetes").Produces(restful.MIME_JSON)
+		ws.Filter(validateXSRFFilter(csrfKey, ws.RootPath()))
+		ws.Route(ws.Method(c.method).Path("/cronjob/{namespace}/{name}/trigger").
+			To(func(request *restful.Request, response *restful.Response) {
+				response.WriteHeader(http.StatusOK)
+			}))
+		container := restful.NewContainer()
+		container.Add(ws)
+
+		req := httptest.NewRequest(c.method, "/api/v1/kubernetes/cronjob/default/test/trigger", nil)
+		req.Header.Set(csrfTokenHeader, c.token)
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, req)
+
+		if recorder.Code != c.expected {
+			t.Errorf("validateXSRFFilter(%s, %s): expected status %d but got %d", c.method, c.token,
+				c.expected, recorder.Code)
+		}
+	}
+}
+
+func TestInstallCluster(t *testing.T) {
+	cases := []struct {
+		method, url, token string
+		expected           int
+		expectedCluster    string
+	}{
+		{http.MethodGet, "/api/v1/cluster/dev/kubernetes/resources", "", http.StatusOK, "dev"},
+		{http.MethodGet, "/api/v1/kubernetes/resources", "", http.StatusOK, ""},
+		{http.MethodPut, "/api/v1/cluster/dev/kubernetes/_raw/secret/namespace/default/name/test", "",
+			http.StatusForbidden, ""},
+		// Valid CSRF token passes the request on to the protected resources check.
+		{http.MethodPut, "/api/v1/cluster/dev/kubernetes/_raw/secret/namespace/default/name/kube-portal-csrf",
+			xsrftoken.Generate("test-key", csrfTokenUser, "_raw"), http.StatusUnauthorized, ""},
+	}
+
+	manager := &fakeKubernetesManager{csrfKey: "test-key"}
+	apiHandler := APIHandler{kManager: manager}
+	ws := new(restful.WebService)
+	ws.Path("/api/v1/cluster").Consumes(restful.MIME_JSON).Produces(restful.MIME_JSON)
+	apiHandler.installCluster(ws)
+
+	// Routes of the default cluster are served by other web service, which must not clash with the cluster one.
+	k8sWs := new(restful.WebService)
+	k8sWs.Path("/api/v1/kubernetes").Consumes(restful.MIME_JSON).Produces(restful.MIME_JSON)
+	InstallFilters(k8sWs, manager)
+	apiHandler.installKubernetes(k8sWs, "")
+
+	container := restful.NewContainer()
+	container.Add(ws)
+	container.Add(k8sWs)
+
+	paths := map[string]bool{}
+	for _, route := range ws.Routes() {
+		paths[route.Path] = true
+	}
+
+	for _, path := range []string{"/api/v1/cluster/{cluster}/kubernetes/pod",
+		"/api/v1/cluster/{cluster}/cani/{verb}/{kind}"} {
+		if !paths[path] {
+			t.Errorf("installCluster(): expected route %s to be installed", path)
+		}
+	}
+
+	for _, c := range cases {
+		manager.cluster = ""
+		req := httptest.NewRequest(c.method, c.url, strings.NewReader("{}"))
+		req.Header.Set("Content-Type", restful.MIME_JSON)
+		req.Header.Set(csrfTokenHeader, c.token)
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, req)
+
+		if recorder.Code != c.expected || manager.cluster != c.expectedCluster {
+			t.Errorf("installCluster(%s %s): expected status %d with cluster %q but got %d with %q", c.method,
+				c.url, c.expected, c.expectedCluster, recorder.Code, manager.cluster)
+		}
+	}
+}
+
+func TestMapRouteToAction(t *testing.T) {
+	cases := []struct {
+		routePath, rootPath, expected string
+	}{
+		{"/api/v1/kubernetes/cronjob/{namespace}/{name}/trigger", "/api/v1/kubernetes", "cronjob"},
+		{"/api/v1/authentication/login", "/api/v1/authentication", "login"},
+		{"/api/v1/authentication/token/refresh", "/api/v1/authentication", "token"},
+	}
+
+	for _, c := range cases {
+		if actual := mapRouteToAction(c.routePath, c.rootPath); actual != c.expected {
+			t.Errorf("mapRouteToAction(%s, %s) == %s, expected %s", c.routePath, c.rootPath, actual, c.expected)
+		}
+	}
+}
+
+func TestRestrictedResourcesFilter(t *testing.T) {
+	cases := []struct {
+		route, url string
+		expected   int
+	}{
+		{"/secret/{namespace}", "/api/v1/kubernetes/secret/kube-system", http.StatusOK},
+		{"/secret/{namespace}/{name}", "/api/v1/kubernetes/secret/kube-system/test-secret", http.StatusOK},
+		{"/secret/{namespace}/{name}", "/api/v1/kubernetes/secret/kube-system/kube-portal-csrf",
+			http.StatusUnauthorized},
+		{"/secret/{namespace}/{name}", "/api/v1/kubernetes/secret/default/kube-portal-csrf", http.StatusOK},
+		{"/configmap/{namespace}/{name}", "/api/v1/kubernetes/configmap/default/test-config",
+			http.StatusUnauthorized},
+		{"/_raw/{kind}/namespace/{namespace}/name/{name}",
+			"/api/v1/kubernetes/_raw/secret/namespace/kube-system/name/kubernetes-dashboard-key-holder",
+			http.StatusUnauthorized},
+	}
+
+	args.GetHolderBuilder().SetNamespace("kube-system").
+		SetProtectedResources([]string{"configmap/test-config"})
+	defer func() {
+		args.GetHolderBuilder().SetNamespace("").SetProtectedResources(nil)
+	}()
+
+	for _, c := range cases {
+		ws := new(restful.WebService)
+		ws.Path("/api/v1/kubernetes").Produces(restful.MIME_JSON)
+		ws.Filter(restrictedResourcesFilter(ws.RootPath()))
+		ws.Route(ws.GET(c.route).To(func(request *restful.Request, response *restful.Response) {
+			response.WriteHeader(http.StatusOK)
+		}))
+		container := restful.NewContainer()
+		container.Add(ws)
+
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, c.url, nil))
+
+		if recorder.Code != c.expected {
+			t.Errorf("restrictedResourcesFilter(%s): expected status %d but got %d", c.url, c.expected,
+				recorder.Code)
+		}
+	}
+}
+
+func TestLoginRateLimitFilter(t *testing.T) {
+	cases := []struct {
+		advance            time.Duration
+		username, password string
+		remoteAddr         string
+		expected           int
+		expectedRetryAfter string
+	}{
+		{0, "jane", "wrong", "10.0.0.1:1234", http.StatusOK, ""},
+		// Every connection comes from other port of the same address.
+		{0, "jane", "right", "10.0.0.1:1235", http.StatusTooManyRequests, "1"},
+		{time.Second, "jane", "wrong", "10.0.0.1:1236", http.StatusOK, ""},
+		// Backoff doubles with every failure.
+		{time.Second, "jane", "right", "10.0.0.1:1237", http.StatusTooManyRequests, "1"},
+		// User is locked out on every address, other users only wait for their address to back off.
+		{time.Second, "jane", "wrong", "10.0.0.2:1234", http.StatusOK, ""},
+		{time.Second, "jane", "right", "10.0.0.3:1234", http.StatusTooManyRequests, "59"},
+		{0, "john", "right", "10.0.0.3:1235", http.StatusOK, ""},
+		{0, "john", "right", "10.0.0.1:1238", http.StatusOK, ""},
+		{time.Minute, "jane", "right", "10.0.0.1:1239", http.StatusOK, ""},
+		// Successful login resets the counters.
+		{0, "jane", "wrong", "10.0.0.1:1240", http.StatusOK, ""},
+		{time.Second, "jane", "right", "10.0.0.1:1241", http.StatusOK, ""},
+		// Other users are limited by the address regardless of the port.
+		{0, "anna", "wrong", "[::1]:1234", http.StatusOK, ""},
+		{0, "paul", "right", "[::1]:1235", http.StatusTooManyRequests, "1"},
+	}
+
+	now := time.Unix(0, 0)
+	limiter := newLoginLimiter(loginLimits{
+		maxAttemptsPerUser: 3,
+		maxAttemptsPerIP:   10,
+		backoff:            time.Second,
+		lockout:            time.Minute,
+	})
+	limiter.now = func() time.Time { return now }
+
+	ws := new(restful.WebService)
+	ws.Path("/api/v1/authentication").Consumes(restful.MIME_JSON).Produces(restful.MIME_JSON)
+	ws.Filter(loginRateLimitFilter(limiter, ws.RootPath()))
+	ws.Route(ws.POST("/login").To(func(request *restful.Request, response *restful.Response) {
+		spec := new(authApi.LoginSpec)
+		if err := request.ReadEntity(spec); err != nil {
+			t.Fatalf("loginRateLimitFilter(): expected request body to be readable but got: %s", err)
+		}
+
+		request.SetAttribute(authApi.LoginSucceededAttribute, spec.Password == "right")
+		response.WriteHeader(http.StatusOK)
+	}))
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	for i, c := range cases {
+		now = now.Add(c.advance)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/authentication/login",
+			strings.NewReader(`{"username": "`+c.username+`", "password": "`+c.password+`"}`))
+		req.Header.Set("Content-Type", restful.MIME_JSON)
+		req.RemoteAddr = c.remoteAddr
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, req)
+
+		if recorder.Code != c.expected || recorder.Header().Get("Retry-After") != c.expectedRetryAfter {
+			t.Errorf("loginRateLimitFilter() case %d: expected status %d with Retry-After %q but got %d with %q", i,
+				c.expected, c.expectedRetryAfter, recorder.Code, recorder.Header().Get("Retry-After"))
+		}
+
+		if recorder.Code == http.StatusTooManyRequests &&
+			!strings.Contains(recorder.Body.String(), errors.MsgLoginTooManyAttemptsError) {
+			t.Errorf("loginRateLimitFilter() case %d: expected body to contain %s but got %s", i,
+				errors.MsgLoginTooManyAttemptsError, recorder.Body.String())
+		}
+	}
+
+	// Bodies are never buffered beyond the limit.
+	body := `{"username": "jane", "password": "` + strings.Repeat("x", maxLoginBodySize) + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/authentication/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", restful.MIME_JSON)
+	recorder := httptest.NewRecorder()
+	container.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("loginRateLimitFilter(): expected status %d for too large body but got %d",
+			http.StatusRequestEntityTooLarge, recorder.Code)
+	}
+}
+
+func TestLoginLimiterReserve(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := newLoginLimiter(loginLimits{maxAttemptsPerUser: 3, backoff: time.Second, lockout: time.Minute})
+	limiter.now = func() time.Time { return now }
+	keys := limiter.keys("10.0.0.1", "jane")
+
+	// Attempts still in progress count as failed, so parallel attempts have to wait for them.
+	if wait := limiter.reserve(keys); wait != 0 {
+		t.Fatalf("reserve(): expected first attempt to pass but got wait %s", wait)
+	}
+
+	if wait := limiter.reserve(keys); wait != time.Second {
+		t.Errorf("reserve(): expected parallel attempt to wait %s but got %s", time.Second, wait)
+	}
+
+	limiter.success("user:jane")
+	if wait := limiter.reserve(keys); wait != 0 {
+		t.Errorf("reserve(): expected attempt after success to pass but got wait %s", wait)
+	}
+}
+
+type fakeKubernetesManager struct {
+	k8sApi.KubernetesManager
+	configErr error
+	stats     k8sApi.ClientCacheStats
+	resources []k8sApi.APIResource
+	csrfKey   string
+	// cluster is the cluster path parameter of the last request that listed the resources.
+	cluster string
+}
+
+func (self *fakeKubernetesManager) APIResources(req *restful.Request) (*k8sApi.APIResourceList, error) {
+	self.cluster = req.PathParameter(k8sApi.ClusterPathParameter)
+	return &k8sApi.APIResourceList{Resources: self.resources}, nil
+}
+
+func (self *fakeKubernetesManager) APIResource(_ *restful.Request, group, version,
+	resource string) (*k8sApi.APIResource, error) {
+	for _, r := range self.resources {
+		if r.Group == group && r.Version == version && r.Resource == resource {
+			return &r, nil
+		}
+	}
+
+	return nil, errors.NewNotFound(resource)
+}
+
+func (self *fakeKubernetesManager) DynamicKubernetes(*restful.Request) (dynamic.Interface, error) {
+	return nil, errors.NewNotFound("object")
+}
+
+func (self *fakeKubernetesManager) Config(*restful.Request) (*rest.Config, error) {
+	return &rest.Config{}, self.configErr
+}
+
+func (self *fakeKubernetesManager) CSRFKey() string {
+	return self.csrfKey
+}
+
+func (self *fakeKubernetesManager) ClientCacheStats() k8sApi.ClientCacheStats {
+	return self.stats
+}
+
+func TestHandleGetClientCacheStats(t *testing.T) {
+	cases := []struct {
+		configErr    error
+		expectedCode int
+		expected     string
+	}{
+		{nil, http.StatusOK, `"hits": 2`},
+		{errors.NewUnauthorized(errors.MsgLoginUnauthorizedError), http.StatusUnauthorized, ""},
+	}
+
+	for _, c := range cases {
+		apiHandler := APIHandler{kManager: &fakeKubernetesManager{
+			configErr: c.configErr,
+			stats:     k8sApi.ClientCacheStats{Hits: 2, Misses: 1, Size: 1},
+		}}
+		ws := new(restful.WebService)
+		ws.Path("/api/v1/stats").Produces(restful.MIME_JSON)
+		apiHandler.installStats(ws)
+		container := restful.NewContainer()
+		container.Add(ws)
+
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/stats/clientcache", nil))
+
+		if recorder.Code != c.expectedCode || !strings.Contains(recorder.Body.String(), c.expected) {
+			t.Errorf("handleGetClientCacheStats(): expected status %d with %s but got %d %s", c.expectedCode,
+				c.expected, recorder.Code, recorder.Body.String())
+		}
+	}
+}
+
+func TestAPIResourceProtectedResources(t *testing.T) {
+	cases := []struct {
+		url      string
+		expected int
+	}{
+		{"/api/v1/kubernetes/resources/apps/v1/deployments/kube-system/coredns", http.StatusForbidden},
+		{"/api/v1/kubernetes/resources/extensions/v1beta1/deployments/kube-system/coredns", http.StatusForbidden},
+		{"/api/v1/kubernetes/resources/apps/v1/deployments/default/coredns", http.StatusNotFound},
+		{"/api/v1/kubernetes/resources/rbac.authorization.k8s.io/v1/clusterroles/cluster-admin",
+			http.StatusForbidden},
+		{"/api/v1/kubernetes/resources/rbac.authorization.k8s.io/v1/clusterroles/view", http.StatusNotFound},
+		{"/api/v1/kubernetes/resources/core/v1/secrets/kube-system/kube-portal-csrf", http.StatusForbidden},
+		// Cluster scoped resource addressed with the namespaced route shape.
+		{"/api/v1/kubernetes/resources/rbac.authorization.k8s.io/v1/clusterroles/kube-system/cluster-admin",
+			http.StatusBadRequest},
+	}
+
+	args.GetHolderBuilder().SetNamespace("kube-system").
+		SetProtectedResources([]string{"deployment/kube-system/coredns", "clusterrole/cluster-admin"})
+	defer func() {
+		args.GetHolderBuilder().SetNamespace("").SetProtectedResources(nil)
+	}()
+
+	apiHandler := APIHandler{kManager: &fakeKubernetesManager{resources: []k8sApi.APIResource{
+		{Group: "apps", Version: "v1", Resource: "deployments", Kind: "Deployment", Namespaced: true},
+		{Group: "extensions", Version: "v1beta1", Resource: "deployments", Kind: "Deployment", Namespaced: true},
+		{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles", Kind: "ClusterRole"},
+		{Version: "v1", Resource: "secrets", Kind: "Secret", Namespaced: true},
+	}}}
+	ws := new(restful.WebService)
+	ws.Path("/api/v1/kubernetes").Produces(restful.MIME_JSON)
+	apiHandler.installAPIResource(ws, "")
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	for _, c := range cases {
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, c.url, nil))
+
+		if recorder.Code != c.expected {
+			t.Errorf("getResourceDetail(%s): expected status %d but got %d", c.url, c.expected, recorder.Code)
+		}
+	}
+}
+
+func TestHandleGetAPIResourceList(t *testing.T) {
+	cases := []struct {
+		configErr error
+		expected  int
+	}{
+		{nil, http.StatusOK},
+		{errors.NewUnauthorized(errors.MsgLoginUnauthorizedError), http.StatusUnauthorized},
+	}
+
+	for _, c := range cases {
+		apiHandler := APIHandler{kManager: &fakeKubernetesManager{configErr: c.configErr}}
+		ws := new(restful.WebService)
+		ws.Path("/api/v1/kubernetes").Produces(restful.MIME_JSON)
+		apiHandler.installAPIResource(ws, "")
+		container := restful.NewContainer()
+		container.Add(ws)
+
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/kubernetes/resources", nil))
+
+		if recorder.Code != c.expected {
+			t.Errorf("handleGetAPIResourceList(): expected status %d but got %d", c.expected, recorder.Code)
+		}
+	}
+}
+
+func TestFollowLogsWithURLToken(t *testing.T) {
+	// User's credentials are sent only over TLS.
+	apiserver := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Write([]byte("2021-01-01T00:00:00Z first line\n"))
+	}))
+	defer apiserver.Close()
+
+	kubeConfig, err := ioutil.TempFile("", "kubeconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(kubeConfig.Name())
+
+	fmt.Fprintf(kubeConfig, `apiVersion: v1
+kind: Config
+clusters:
+- name: test-cluster
+  cluster:
+    server: %s
+    insecure-skip-tls-verify: true
+contexts:
+- name: test-context
+  context:
+    cluster: test-cluster
+current-context: test-context
+`, apiserver.URL)
+	kubeConfig.Close()
+
+	args.GetHolderBuilder().SetEnableSkipLogin(false)
+	tokenManager := jwe.NewJWETokenManager(jwe.NewRSAKeyHolder(fake.NewSimpleClientset(), "kube-portal"))
+	kManager := kubernetes.NewKubernetesManager(kubeConfig.Name(), "")
+	kManager.SetTokenManager(tokenManager)
+	apiHandler := APIHandler{kManager: kManager}
+	ws := new(restful.WebService)
+	ws.Path("/api/v1/kubernetes").Produces(restful.MIME_JSON)
+	apiHandler.installLog(ws, "")
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	// Browser reads the token with its session token in the header and passes it to EventSource in the URL.
+	followPath := "/api/v1/kubernetes/log/follow/default/test-pod/app"
+	sessionToken, _ := tokenManager.Generate(api.AuthInfo{Token: "test-token"}, "jane", "")
+	req := httptest.NewRequest(http.MethodGet, followPath+"/token", nil)
+	req.Header.Set(kubernetes.JWETokenHeader, sessionToken)
+	recorder := httptest.NewRecorder()
+	container.ServeHTTP(recorder, req)
+
+	tokenResponse := k8sApi.URLTokenResponse{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &tokenResponse); err != nil || recorder.Code != http.StatusOK {
+		t.Fatalf("handleGetFollowLogsToken(): expected token but got %d %s", recorder.Code, recorder.Body.String())
+	}
+
+	cases := []struct {
+		path, token, header string
+		expected            int
+	}{
+		{followPath, tokenResponse.Token, "", http.StatusOK},
+		{followPath, "", "", http.StatusUnauthorized},
+		// Token is bound to the container it has been issued for.
+		{"/api/v1/kubernetes/log/follow/default/test-pod/sidecar", tokenResponse.Token, "", http.StatusUnauthorized},
+		// Token passed in URLs is never accepted in place of the session token.
+		{followPath, "", tokenResponse.Token, http.StatusUnauthorized},
+	}
+
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, c.path+"?token="+c.token, nil)
+		req.Header.Set(kubernetes.JWETokenHeader, c.header)
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, req)
+
+		if recorder.Code != c.expected {
+			t.Errorf("handleFollowLogs(%s): expected status %d but got %d %s", c.path, c.expected, recorder.Code,
+				recorder.Body.String())
+		}
+
+		if c.expected == http.StatusOK && !strings.Contains(recorder.Body.String(), "first line") {
+			t.Errorf("handleFollowLogs(%s): expected log event but got %s", c.path, recorder.Body.String())
+		}
+	}
+}
+
+func TestHTTPSRedirectHandler(t *testing.T) {
+	cases := []struct {
+		securePort       int
+		url, expectedURL string
+	}{
+		{8443, "http://portal.example.com:9090/api/v1/pod?page=1", "https://portal.example.com:8443/api/v1/pod?page=1"},
+		{443, "http://portal.example.com/api/v1/pod", "https://portal.example.com/api/v1/pod"},
+		{443, "http://[::1]:9090/", "https://[::1]/"},
+	}
+
+	for _, c := range cases {
+		recorder := httptest.NewRecorder()
+		NewHTTPSRedirectHandler(c.securePort).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, c.url, nil))
+
+		if recorder.Code != http.StatusPermanentRedirect || recorder.Header().Get("Location") != c.expectedURL {
+			t.Errorf("NewHTTPSRedirectHandler(%d): Expected redirect of %s to %s but got %d %s", c.securePort, c.url,
+				c.expectedURL, recorder.Code, recorder.Header().Get("Location"))
+		}
+	}
+}
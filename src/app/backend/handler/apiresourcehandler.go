@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/docs"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/handler/parser"
+	k8sApi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/apiresource"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/common"
+)
+
+func (apiHandler *APIHandler) installAPIResource(ws *restful.WebService, prefix string) {
+	ws.Route(
+		ws.GET(prefix+"/resources").
+			To(apiHandler.handleGetAPIResourceList).
+			Returns(200, "OK", k8sApi.APIResourceList{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Doc("List resources served by the cluster").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.APIResourceDocsTag}))
+	ws.Route(
+		ws.GET(prefix+"/resources/{group}/{version}/{resource}").
+			To(apiHandler.handleGetResourceList).
+			Param(ws.PathParameter("group", "API group, core for the core group").Required(true)).
+			Param(ws.PathParameter("version", "API version").Required(true)).
+			Param(ws.PathParameter("resource", "Resource `e.g. deployments`").Required(true)).
+			Returns(200, "OK", apiresource.ResourceList{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Returns(404, "Not Found", errors.StatusErrorResponse{}).
+			Doc("List objects of the resource").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.APIResourceDocsTag}))
+	ws.Route(
+		ws.GET(prefix+"/resources/{group}/{version}/{resource}/{namespace}").
+			To(apiHandler.handleGetResourceListNamespace).
+			Param(ws.PathParameter("group", "API group, core for the core group").Required(true)).
+			Param(ws.PathParameter("version", "API version").Required(true)).
+			Param(ws.PathParameter("resource", "Resource `e.g. deployments`").Required(true)).
+			Param(ws.PathParameter("namespace",
+				"Query for Namespace. Name of the object for cluster scoped resources").Required(true)).
+			Returns(200, "OK", apiresource.ResourceList{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Returns(403, "Forbidden", errors.StatusErrorResponse{}).
+			Returns(404, "Not Found", errors.StatusErrorResponse{}).
+			Doc("List objects of the namespaced resource in the Namespace or read the object of the cluster "+
+				"scoped resource").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.APIResourceDocsTag}))
+	ws.Route(
+		ws.GET(prefix+"/resources/{group}/{version}/{resource}/{namespace}/{name}").
+			To(apiHandler.handleGetResourceDetail).
+			Param(ws.PathParameter("group", "API group, core for the core group").Required(true)).
+			Param(ws.PathParameter("version", "API version").Required(true)).
+			Param(ws.PathParameter("resource", "Resource `e.g. deployments`").Required(true)).
+			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
+			Param(ws.PathParameter("name", "Name of the object").Required(true)).
+			Returns(200, "OK", apiresource.ResourceDetail{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Returns(403, "Forbidden", errors.StatusErrorResponse{}).
+			Returns(404, "Not Found", errors.StatusErrorResponse{}).
+			Doc("Read the specified object of the namespaced resource").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.APIResourceDocsTag}))
+}
+
+func (apiHandler *APIHandler) handleGetAPIResourceList(request *restful.Request, response *restful.Response) {
+	// Discovery is cached and read with the backend's credentials, but only users able to use the portal can
+	// read it.
+	if _, err := apiHandler.kManager.Config(request); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := apiHandler.kManager.APIResources(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetResourceList(request *restful.Request, response *restful.Response) {
+	apiHandler.getResourceList(request, response, common.NewNamespaceQuery(nil))
+}
+
+func (apiHandler *APIHandler) handleGetResourceListNamespace(request *restful.Request, response *restful.Response) {
+	resource, err := apiHandler.apiResource(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	if !resource.Namespaced {
+		apiHandler.getResourceDetail(request, response, resource, "", request.PathParameter("namespace"))
+		return
+	}
+
+	apiHandler.getResourceList(request, response, parseNamespacePathParameter(request))
+}
+
+func (apiHandler *APIHandler) handleGetResourceDetail(request *restful.Request, response *restful.Response) {
+	resource, err := apiHandler.apiResource(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	if !resource.Namespaced {
+		errors.HandleInternalError(response, errors.NewBadRequest("namespace set for cluster scoped resource"))
+		return
+	}
+
+	apiHandler.getResourceDetail(request, response, resource, request.PathParameter("namespace"),
+		request.PathParameter("name"))
+}
+
+func (apiHandler *APIHandler) getResourceList(request *restful.Request, response *restful.Response,
+	namespace *common.NamespaceQuery) {
+	resource, err := apiHandler.apiResource(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	client, err := apiHandler.kManager.DynamicKubernetes(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	dataSelect := parser.ParseDataSelectPathParameter(request)
+	result, err := apiresource.GetResourceList(client, *resource, namespace, dataSelect)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) getResourceDetail(request *restful.Request, response *restful.Response,
+	resource *k8sApi.APIResource, namespace, name string) {
+	// Routes of this handler do not have the kind path parameter, so the restricted resources filter can not
+	// recognize protected objects.
+	for _, kind := range portalKinds(resource) {
+		if isProtectedResource(kind, namespace, name) {
+			errors.HandleInternalError(response, errors.NewGenericResponse(http.StatusForbidden,
+				errors.MsgDashboardExclusiveResourceError))
+			return
+		}
+	}
+
+	client, err := apiHandler.kManager.DynamicKubernetes(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := apiresource.GetResourceDetail(client, *resource, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) apiResource(request *restful.Request) (*k8sApi.APIResource, error) {
+	return apiHandler.kManager.APIResource(request, apiresource.ToGroup(request.PathParameter("group")),
+		request.PathParameter("version"), request.PathParameter("resource"))
+}
+
+// portalKinds returns kinds protected resources of the resource can be configured with. Those are the kind found by
+// the discovery and the kinds of the portal served by the resource in any group, e.g. deployment for deployments
+// of both the apps and extensions groups.
+func portalKinds(resource *k8sApi.APIResource) []string {
+	result := []string{strings.ToLower(resource.Kind)}
+	for kind, mapping := range api.KindToAPIMapping {
+		if mapping.Resource == resource.Resource && kind != result[0] {
+			result = append(result, kind)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/docs"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+	k8sApi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
+)
+
+// Maximal number of actions that can be checked with a single batch request.
+const maxCanIActions = 100
+
+func (apiHandler *APIHandler) installCanI(ws *restful.WebService, prefix string) {
+	ws.Route(
+		ws.GET(prefix+"/{verb}/{kind}").
+			To(apiHandler.handleCanI).
+			Param(ws.PathParameter("verb", "Verb to check `e.g. get, list, create, delete`").Required(true)).
+			Param(ws.PathParameter("kind", "Kind of resource `e.g. pod or cronjob`").Required(true)).
+			Param(ws.QueryParameter("subresource", "Subresource to check `e.g. log or exec`")).
+			Returns(200, "OK", k8sApi.CanIResponse{}).
+			Returns(400, "Bad Request", errors.StatusErrorResponse{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Doc("Check whether the user can perform the action on the kind in all namespaces or cluster wide").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.CanIDocsTag}))
+	ws.Route(
+		ws.GET(prefix+"/{verb}/{kind}/{namespace}").
+			To(apiHandler.handleCanI).
+			Param(ws.PathParameter("verb", "Verb to check `e.g. get, list, create, delete`").Required(true)).
+			Param(ws.PathParameter("kind", "Kind of resource `e.g. pod or cronjob`").Required(true)).
+			Param(ws.PathParameter("namespace", "Namespace, ignored for cluster scoped kinds").Required(true)).
+			Param(ws.QueryParameter("subresource", "Subresource to check `e.g. log or exec`")).
+			Returns(200, "OK", k8sApi.CanIResponse{}).
+			Returns(400, "Bad Request", errors.StatusErrorResponse{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Doc("Check whether the user can perform the action on the kind in the Namespace").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.CanIDocsTag}))
+	ws.Route(
+		ws.GET(prefix+"/{verb}/{kind}/{namespace}/{name}").
+			To(apiHandler.handleCanI).
+			Param(ws.PathParameter("verb", "Verb to check `e.g. get, update, delete`").Required(true)).
+			Param(ws.PathParameter("kind", "Kind of resource `e.g. pod or cronjob`").Required(true)).
+			Param(ws.PathParameter("namespace", "Namespace, ignored for cluster scoped kinds").Required(true)).
+			Param(ws.PathParameter("name", "Name of resource").Required(true)).
+			Param(ws.QueryParameter("subresource", "Subresource to check `e.g. log or exec`")).
+			Returns(200, "OK", k8sApi.CanIResponse{}).
+			Returns(400, "Bad Request", errors.StatusErrorResponse{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Doc("Check whether the user can perform the action on the specified resource").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.CanIDocsTag}))
+	ws.Route(
+		ws.POST(prefix).
+			To(apiHandler.handleCanIBatch).
+			Reads(k8sApi.CanIBatchSpec{}).
+			Returns(200, "OK", k8sApi.CanIBatchResponse{}).
+			Returns(400, "Bad Request", errors.StatusErrorResponse{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Doc(fmt.Sprintf("Check up to %d actions at once", maxCanIActions)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.CanIDocsTag}))
+}
+
+func (apiHandler *APIHandler) handleCanI(request *restful.Request, response *restful.Response) {
+	ssar, err := k8sApi.ToKindSelfSubjectAccessReview(
+		request.PathParameter("namespace"),
+		request.PathParameter("name"),
+		request.PathParameter("kind"),
+		request.QueryParameter("subresource"),
+		request.PathParameter("verb"))
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	// CanI treats every error as a denial, make sure that missing credentials are reported as such.
+	if _, err = apiHandler.kManager.Config(request); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, k8sApi.CanIResponse{
+		Allowed: apiHandler.kManager.CanI(request, ssar),
+	})
+}
+
+func (apiHandler *APIHandler) handleCanIBatch(request *restful.Request, response *restful.Response) {
+	spec := new(k8sApi.CanIBatchSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		errors.HandleInternalError(response, errors.NewBadRequest(err.Error()))
+		return
+	}
+
+	if len(spec.Actions) > maxCanIActions {
+		errors.HandleInternalError(response, errors.NewBadRequest(
+			fmt.Sprintf("at most %d actions can be checked at once", maxCanIActions)))
+		return
+	}
+
+	if _, err := apiHandler.kManager.Config(request); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result := k8sApi.CanIBatchResponse{Actions: make([]k8sApi.CanIActionResponse, 0, len(spec.Actions))}
+	for _, action := range spec.Actions {
+		ssar, err := k8sApi.ToKindSelfSubjectAccessReview(action.Namespace, action.Name, action.Kind,
+			action.Subresource, action.Verb)
+		if err != nil {
+			errors.HandleInternalError(response, err)
+			return
+		}
+
+		result.Actions = append(result.Actions, k8sApi.CanIActionResponse{
+			CanIAction: action,
+			Allowed:    apiHandler.kManager.CanI(request, ssar),
+		})
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/docs"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+	k8sApi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/cluster"
+)
+
+// installCluster installs the list of clusters and the Kubernetes and CanI routes served by the cluster selected
+// with the cluster path parameter, e.g. /api/v1/cluster/{cluster}/kubernetes/pod. Routes of the selected cluster
+// are installed with an explicit path prefix, because container can register every path prefix only once.
+func (apiHandler *APIHandler) installCluster(ws *restful.WebService) {
+	clusterPath := "/{" + k8sApi.ClusterPathParameter + "}"
+	installFilters(ws, apiHandler.kManager, ws.RootPath()+clusterPath+"/kubernetes")
+	apiHandler.installKubernetes(ws, clusterPath+"/kubernetes")
+	apiHandler.installCanI(ws, clusterPath+"/cani")
+
+	ws.Route(
+		ws.GET("").
+			To(apiHandler.handleGetClusterList).
+			Returns(200, "OK", cluster.ClusterList{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Doc("List clusters served by the portal with their versions and reachability").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.ClusterDocsTag}))
+}
+
+func (apiHandler *APIHandler) handleGetClusterList(request *restful.Request, response *restful.Response) {
+	// Versions are read with the backend's credentials, but only users able to use the portal can list them.
+	if _, err := apiHandler.kManager.Config(request); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, cluster.GetClusterList(apiHandler.kManager))
+}
@@ -12,16 +12,16 @@ import (
 	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/clusterrolebinding"
 )
 
-func (apiHandler *APIHandler) installClusterRoleBinding(ws *restful.WebService) {
+func (apiHandler *APIHandler) installClusterRoleBinding(ws *restful.WebService, prefix string) {
 	ws.Route(
-		ws.GET("/clusterrolebinding").
+		ws.GET(prefix+"/clusterrolebinding").
 			To(apiHandler.handleGetClusterRoleBindingList).
 			Returns(200, "OK", clusterrolebinding.ClusterRoleBindingList{}).
 			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
 			Doc("List objects of kind ClusterRoleBinding").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.ClusterRoleBindingDocsTag}))
 	ws.Route(
-		ws.GET("/clusterrolebinding/{name}").
+		ws.GET(prefix+"/clusterrolebinding/{name}").
 			To(apiHandler.handleGetClusterRoleBindingDetail).
 			Param(ws.PathParameter("name", "Name of ClusterRoleBinding").Required(true)).
 			Returns(200, "OK", clusterrolebinding.ClusterRoleBindingDetail{}).
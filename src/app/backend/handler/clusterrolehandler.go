@@ -12,16 +12,16 @@ import (
 	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/clusterrole"
 )
 
-func (apiHandler *APIHandler) installClusterRole(ws *restful.WebService) {
+func (apiHandler *APIHandler) installClusterRole(ws *restful.WebService, prefix string) {
 	ws.Route(
-		ws.GET("/clusterrole").
+		ws.GET(prefix+"/clusterrole").
 			To(apiHandler.handleGetClusterRoleList).
 			Returns(200, "OK", clusterrole.ClusterRoleList{}).
 			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
 			Doc("List objects of kind ClusterRole").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.ClusterRoleDocsTag}))
 	ws.Route(
-		ws.GET("/clusterrole/{name}").
+		ws.GET(prefix+"/clusterrole/{name}").
 			To(apiHandler.handleGetClusterRoleDetail).
 			Param(ws.PathParameter("name", "Name of ClusterRole").Required(true)).
 			Returns(200, "OK", clusterrole.ClusterRoleDetail{}).
@@ -13,16 +13,16 @@ import (
 	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/configmap"
 )
 
-func (apiHandler *APIHandler) installConfigMap(ws *restful.WebService) {
+func (apiHandler *APIHandler) installConfigMap(ws *restful.WebService, prefix string) {
 	ws.Route(
-		ws.GET("/configmap").
+		ws.GET(prefix+"/configmap").
 			To(apiHandler.handleGetConfigMapList).
 			Returns(200, "OK", configmap.ConfigMapList{}).
 			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
 			Doc("List objects of kind ConfigMap").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.ConfigMapDocsTag}))
 	ws.Route(
-		ws.GET("/configmap/{namespace}").
+		ws.GET(prefix+"/configmap/{namespace}").
 			To(apiHandler.handleGetConfigMapListNamespace).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Returns(200, "OK", configmap.ConfigMapList{}).
@@ -30,7 +30,7 @@ func (apiHandler *APIHandler) installConfigMap(ws *restful.WebService) {
 			Doc("List objects of kind ConfigMap in the Namespace").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.ConfigMapDocsTag}))
 	ws.Route(
-		ws.GET("/configmap/{namespace}/{name}").
+		ws.GET(prefix+"/configmap/{namespace}/{name}").
 			To(apiHandler.handleGetConfigMapDetail).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Param(ws.PathParameter("name", "Name of ConfigMap").Required(true)).
@@ -15,16 +15,16 @@ import (
 	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/job"
 )
 
-func (apiHandler *APIHandler) installCronJob(ws *restful.WebService) {
+func (apiHandler *APIHandler) installCronJob(ws *restful.WebService, prefix string) {
 	ws.Route(
-		ws.GET("/cronjob").
+		ws.GET(prefix+"/cronjob").
 			To(apiHandler.handleGetCronJobList).
 			Returns(200, "OK", cronjob.CronJobList{}).
 			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
 			Doc("List objects of kind CronJob").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.CronJobDocsTag}))
 	ws.Route(
-		ws.GET("/cronjob/{namespace}").
+		ws.GET(prefix+"/cronjob/{namespace}").
 			To(apiHandler.handleGetCronJobListNamespace).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Returns(200, "OK", cronjob.CronJobList{}).
@@ -32,7 +32,7 @@ func (apiHandler *APIHandler) installCronJob(ws *restful.WebService) {
 			Doc("List objects of kind CronJob in the Namespace").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.CronJobDocsTag}))
 	ws.Route(
-		ws.GET("/cronjob/{namespace}/{name}").
+		ws.GET(prefix+"/cronjob/{namespace}/{name}").
 			To(apiHandler.handleGetCronJobDetail).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Param(ws.PathParameter("name", "Name of CronJob").DataType("string").Required(true)).
@@ -41,7 +41,7 @@ func (apiHandler *APIHandler) installCronJob(ws *restful.WebService) {
 			Doc("Read the specified CronJob").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.CronJobDocsTag}))
 	ws.Route(
-		ws.GET("/cronjob/{namespace}/{name}/job").
+		ws.GET(prefix+"/cronjob/{namespace}/{name}/job").
 			To(apiHandler.handleGetCronJobJobs).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Param(ws.PathParameter("name", "Name of CronJob").DataType("string").Required(true)).
@@ -50,7 +50,7 @@ func (apiHandler *APIHandler) installCronJob(ws *restful.WebService) {
 			Doc("List Jobs related to a CronJob").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.CronJobDocsTag}))
 	ws.Route(
-		ws.GET("/cronjob/{namespace}/{name}/event").
+		ws.GET(prefix+"/cronjob/{namespace}/{name}/event").
 			To(apiHandler.handleGetCronJobEvents).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Param(ws.PathParameter("name", "Name of CronJob").DataType("string").Required(true)).
@@ -59,7 +59,7 @@ func (apiHandler *APIHandler) installCronJob(ws *restful.WebService) {
 			Doc("List events related to a CronJob").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.CronJobDocsTag}))
 	ws.Route(
-		ws.PUT("/cronjob/{namespace}/{name}/trigger").
+		ws.PUT(prefix+"/cronjob/{namespace}/{name}/trigger").
 			To(apiHandler.handleTriggerCronJob).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Param(ws.PathParameter("name", "Name of CronJob").DataType("string").Required(true)).
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+	"golang.org/x/net/xsrftoken"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/docs"
+)
+
+func (apiHandler *APIHandler) installCsrfToken(ws *restful.WebService) {
+	ws.Route(
+		ws.GET("/{action}").
+			To(apiHandler.handleGetCsrfToken).
+			Param(ws.PathParameter("action",
+				"Action the token is generated for `e.g. login or cronjob`").Required(true)).
+			Returns(200, "OK", api.CsrfToken{}).
+			Doc("Get CSRF token for the action, required by every mutating request in "+csrfTokenHeader+" header").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.CsrfTokenDocsTag}))
+}
+
+func (apiHandler *APIHandler) handleGetCsrfToken(request *restful.Request, response *restful.Response) {
+	action := request.PathParameter("action")
+	token := xsrftoken.Generate(apiHandler.kManager.CSRFKey(), csrfTokenUser, action)
+	response.WriteHeaderAndEntity(http.StatusOK, api.CsrfToken{Token: token})
+}
@@ -10,8 +10,10 @@ import (
 	"time"
 
 	"github.com/emicklei/go-restful/v3"
+	"golang.org/x/net/xsrftoken"
 
 	"github.com/donghoon-khan/kubeportal/src/app/backend/args"
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
 	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
 	k8sapi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
 )
@@ -20,20 +22,98 @@ const (
 	originalForwardedForHeader = "X-Original-Forwarded-For"
 	forwardedForHeader         = "X-Forwarded-For"
 	realIPHeader               = "X-Real-Ip"
+	csrfTokenHeader            = "X-CSRF-TOKEN"
+	// CSRF tokens are not bound to a user, only to an action.
+	csrfTokenUser = "none"
 )
 
 func InstallFilters(ws *restful.WebService, manager k8sapi.KubernetesManager) {
+	installFilters(ws, manager, ws.RootPath())
+}
+
+// installFilters installs filters applied only to routes under the root path, which can be longer than the root
+// path of the web service.
+func installFilters(ws *restful.WebService, manager k8sapi.KubernetesManager, rootPath string) {
 	/*ws.Filter(requestAndResponseLogger)
-	ws.Filter(metricsFilter)
-	ws.Filter(validateXSRFFilter(manager.CSRFKey()))*/
-	//ws.Filter(restrictedResourcesFilter)
+	ws.Filter(metricsFilter)*/
+	ws.Filter(validateXSRFFilter(manager.CSRFKey(), rootPath))
+	ws.Filter(restrictedResourcesFilter(rootPath))
+}
+
+func isUnderRootPath(request *restful.Request, rootPath string) bool {
+	return strings.HasPrefix(request.SelectedRoutePath(), rootPath)
+}
+
+// validateXSRFFilter rejects mutating requests that do not carry a valid CSRF token generated for the action
+// of the request. Tokens can be obtained from the /api/v1/csrftoken/{action} endpoint and are valid for
+// xsrftoken.Timeout.
+func validateXSRFFilter(csrfKey, rootPath string) restful.FilterFunction {
+	return func(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+		if isUnderRootPath(request, rootPath) && shouldDoCsrfValidation(request) && !xsrftoken.Valid(request.HeaderParameter(csrfTokenHeader), csrfKey,
+			csrfTokenUser, mapRouteToAction(request.SelectedRoutePath(), rootPath)) {
+			err := errors.NewGenericResponse(http.StatusForbidden, errors.MsgCsrfValidationError)
+			response.WriteHeaderAndEntity(int(err.ErrStatus.Code), errors.StatusErrorResponse{Message: err.Error()})
+			return
+		}
+
+		chain.ProcessFilter(request, response)
+	}
+}
+
+func shouldDoCsrfValidation(request *restful.Request) bool {
+	switch request.Request.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// mapRouteToAction returns the first segment of the route path after the web service root path,
+// e.g. cronjob for /api/v1/kubernetes/cronjob/{namespace}/{name}/trigger.
+func mapRouteToAction(routePath, rootPath string) string {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(routePath, rootPath), "/"), "/")
+	return parts[0]
 }
 
-func restrictedResourcesFilter(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+// restrictedResourcesFilter rejects requests that read or modify one of the protected resources, e.g. the secret
+// holding the key that encrypts JWE tokens. Kind of the resource is taken from the kind path parameter or, if the
+// route does not have one, from the first segment of the route path.
+func restrictedResourcesFilter(rootPath string) restful.FilterFunction {
+	return func(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+		if !isUnderRootPath(request, rootPath) {
+			chain.ProcessFilter(request, response)
+			return
+		}
+
+		kind := request.PathParameter("kind")
+		if len(kind) == 0 {
+			kind = mapRouteToAction(request.SelectedRoutePath(), rootPath)
+		}
+
+		if !isProtectedResource(kind, request.PathParameter("namespace"), request.PathParameter("name")) {
+			chain.ProcessFilter(request, response)
+			return
+		}
+
+		err := errors.NewUnauthorized(errors.MsgDashboardExclusiveResourceError)
+		response.WriteHeaderAndEntity(int(err.ErrStatus.Code),
+			errors.StatusErrorResponse{Message: err.Error()})
+	}
+}
 
-	err := errors.NewUnauthorized(errors.MsgDashboardExclusiveResourceError)
-	response.WriteHeaderAndEntity(int(err.ErrStatus.Code),
-		errors.StatusErrorResponse{Message: err.Error()})
+func isProtectedResource(kind, namespace, name string) bool {
+	if len(name) == 0 {
+		return false
+	}
+
+	for _, protectedResource := range authApi.ProtectedResources() {
+		if protectedResource.Matches(kind, namespace, name) {
+			return true
+		}
+	}
+
+	return false
 }
 
 func requestAndResponseLogger(request *restful.Request, response *restful.Response,
@@ -75,8 +155,10 @@ func checkSensitiveURL(url *string) bool {
 	var s struct{}
 	var sensitiveUrls = make(map[string]struct{})
 	sensitiveUrls["/api/v1/login"] = s
+	sensitiveUrls["/api/v1/authentication/login"] = s
 	sensitiveUrls["/api/v1/csrftoken/login"] = s
 	sensitiveUrls["/api/v1/token/refresh"] = s
+	sensitiveUrls["/api/v1/authentication/token/refresh"] = s
 
 	if _, ok := sensitiveUrls[*url]; ok {
 		return true
@@ -13,16 +13,16 @@ import (
 	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/ingress"
 )
 
-func (apiHandler *APIHandler) installIngress(ws *restful.WebService) {
+func (apiHandler *APIHandler) installIngress(ws *restful.WebService, prefix string) {
 	ws.Route(
-		ws.GET("/ingress").
+		ws.GET(prefix+"/ingress").
 			To(apiHandler.handleGetIngressList).
 			Returns(200, "OK", ingress.IngressList{}).
 			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
 			Doc("List objects of kind Ingress").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.IngressDocsTag}))
 	ws.Route(
-		ws.GET("/ingress/{namespace}").
+		ws.GET(prefix+"/ingress/{namespace}").
 			To(apiHandler.handleGetIngressListNamespace).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Returns(200, "OK", ingress.IngressList{}).
@@ -30,7 +30,7 @@ func (apiHandler *APIHandler) installIngress(ws *restful.WebService) {
 			Doc("List objects of kind Ingress in the Namespace").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.IngressDocsTag}))
 	ws.Route(
-		ws.GET("/ingress/{namespace}/{name}").
+		ws.GET(prefix+"/ingress/{namespace}/{name}").
 			To(apiHandler.handleGetIngressDetail).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Param(ws.PathParameter("name", "Name of Ingress").Required(true)).
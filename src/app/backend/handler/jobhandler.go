@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"net/http"
+
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/docs"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/handler/parser"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/common"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/dataselect"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/job"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/pod"
+)
+
+func (apiHandler *APIHandler) installJob(ws *restful.WebService, prefix string) {
+	ws.Route(
+		ws.GET(prefix+"/job").
+			To(apiHandler.handleGetJobList).
+			Returns(200, "OK", job.JobList{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Doc("List objects of kind Job").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.JobDocsTag}))
+	ws.Route(
+		ws.GET(prefix+"/job/{namespace}").
+			To(apiHandler.handleGetJobListNamespace).
+			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
+			Returns(200, "OK", job.JobList{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Doc("List objects of kind Job in the Namespace").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.JobDocsTag}))
+	ws.Route(
+		ws.GET(prefix+"/job/{namespace}/{name}").
+			To(apiHandler.handleGetJobDetail).
+			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
+			Param(ws.PathParameter("name", "Name of Job").DataType("string").Required(true)).
+			Returns(200, "OK", job.JobDetail{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Doc("Read the specified Job").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.JobDocsTag}))
+	ws.Route(
+		ws.DELETE(prefix+"/job/{namespace}/{name}").
+			To(apiHandler.handleDeleteJob).
+			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
+			Param(ws.PathParameter("name", "Name of Job").DataType("string").Required(true)).
+			Returns(200, "OK", nil).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Returns(404, "Not Found", errors.StatusErrorResponse{}).
+			Doc("Delete the specified Job together with its Pods").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.JobDocsTag}))
+	ws.Route(
+		ws.GET(prefix+"/job/{namespace}/{name}/pod").
+			To(apiHandler.handleGetJobPods).
+			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
+			Param(ws.PathParameter("name", "Name of Job").DataType("string").Required(true)).
+			Returns(200, "OK", pod.PodList{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Doc("List Pods related to a Job").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.JobDocsTag}))
+	ws.Route(
+		ws.GET(prefix+"/job/{namespace}/{name}/event").
+			To(apiHandler.handleGetJobEvents).
+			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
+			Param(ws.PathParameter("name", "Name of Job").DataType("string").Required(true)).
+			Returns(200, "OK", common.EventList{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Doc("List events related to a Job").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.JobDocsTag}))
+	ws.Route(
+		ws.PUT(prefix+"/job/{namespace}/{name}/rerun").
+			To(apiHandler.handleRerunJob).
+			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
+			Param(ws.PathParameter("name", "Name of Job").DataType("string").Required(true)).
+			Returns(200, "OK", job.Job{}).
+			Returns(400, "Bad Request", errors.StatusErrorResponse{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Doc("Create a new Job with the spec of the specified finished Job").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.JobDocsTag}))
+}
+
+func (apiHandler *APIHandler) handleGetJobList(request *restful.Request, response *restful.Response) {
+	k8s, err := apiHandler.kManager.Kubernetes(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	dataSelect := parser.ParseDataSelectPathParameter(request)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := job.GetJobList(k8s, common.NewNamespaceQuery(nil), dataSelect, apiHandler.iManager.Metric().Client())
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetJobListNamespace(request *restful.Request, response *restful.Response) {
+	k8s, err := apiHandler.kManager.Kubernetes(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := parseNamespacePathParameter(request)
+	dataSelect := parser.ParseDataSelectPathParameter(request)
+	dataSelect.MetricQuery = dataselect.StandardMetrics
+	result, err := job.GetJobList(k8s, namespace, dataSelect, apiHandler.iManager.Metric().Client())
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetJobDetail(request *restful.Request, response *restful.Response) {
+	k8s, err := apiHandler.kManager.Kubernetes(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	result, err := job.GetJobDetail(k8s, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleDeleteJob(request *restful.Request, response *restful.Response) {
+	k8s, err := apiHandler.kManager.Kubernetes(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	if err = job.DeleteJob(k8s, namespace, name); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeader(http.StatusOK)
+}
+
+func (apiHandler *APIHandler) handleGetJobPods(request *restful.Request, response *restful.Response) {
+	k8s, err := apiHandler.kManager.Kubernetes(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	dataSelect := parser.ParseDataSelectPathParameter(request)
+	result, err := job.GetJobPods(k8s, apiHandler.iManager.Metric().Client(), dataSelect, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetJobEvents(request *restful.Request, response *restful.Response) {
+	k8s, err := apiHandler.kManager.Kubernetes(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	dataSelect := parser.ParseDataSelectPathParameter(request)
+	result, err := job.GetJobEvents(k8s, dataSelect, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleRerunJob(request *restful.Request, response *restful.Response) {
+	k8s, err := apiHandler.kManager.Kubernetes(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	result, err := job.RerunJob(k8s, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
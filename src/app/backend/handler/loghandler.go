@@ -0,0 +1,342 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/args"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/docs"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/handler/parser"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes"
+	k8sApi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/container"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/controller"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/logs"
+)
+
+func (apiHandler *APIHandler) installLog(ws *restful.WebService, prefix string) {
+	ws.Route(
+		ws.GET(prefix+"/log/source/{namespace}/{resourceName}/{resourceType}").
+			To(apiHandler.handleGetLogSource).
+			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
+			Param(ws.PathParameter("resourceName", "Name of the resource").Required(true)).
+			Param(ws.PathParameter("resourceType", "Kind of the resource `e.g. pod or job`").Required(true)).
+			Returns(200, "OK", controller.LogSources{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Doc("List Pods and containers the resource has logs of").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.LogDocsTag}))
+	ws.Route(
+		logSelectionParams(ws, ws.GET(prefix+"/log/aggregated/{namespace}/{resourceName}/{resourceType}").
+			To(apiHandler.handleGetAggregatedLogs).
+			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
+			Param(ws.PathParameter("resourceName", "Name of the resource").Required(true)).
+			Param(ws.PathParameter("resourceType", "Kind of the resource `e.g. job or replicaset`").
+				Required(true))).
+			Returns(200, "OK", logs.LogDetails{}).
+			Returns(400, "Bad Request", errors.StatusErrorResponse{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Doc("Read the selected lines of logs of all containers in all Pods of the resource, merged by "+
+				"timestamps. Every line is tagged with its Pod and container. Logs of at most 50 containers are "+
+				"read and the read limit is shared by them, the logs are marked as truncated when it is reached").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.LogDocsTag}))
+	ws.Route(apiHandler.logRoute(ws, prefix+"/log/{namespace}/{pod}"))
+	ws.Route(apiHandler.logRoute(ws, prefix+"/log/{namespace}/{pod}/{container}").
+		Param(ws.PathParameter("container", "Name of the container").Required(true)))
+	ws.Route(
+		ws.GET(prefix+"/log/search/{namespace}/{pod}/{container}").
+			To(apiHandler.handleSearchLogs).
+			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
+			Param(ws.PathParameter("pod", "Name of Pod").Required(true)).
+			Param(ws.PathParameter("container", "Name of the container").Required(true)).
+			Param(ws.QueryParameter("query", "Substring or regular expression to search for").Required(true)).
+			Param(ws.QueryParameter("regex", "Search for the regular expression").DataType("boolean")).
+			Param(ws.QueryParameter("ignoreCase", "Ignore case of letters").DataType("boolean")).
+			Param(ws.QueryParameter("contextLines",
+				fmt.Sprintf("Number of lines returned before and after each match, defaults to %d, at most %d",
+					logs.DefaultSearchContextLines, logs.MaxSearchContextLines)).DataType("int")).
+			Param(ws.QueryParameter("logFilePosition",
+				"Search logs read from the "+logs.Beginning+" or the "+logs.End+" of the log file")).
+			Param(ws.QueryParameter("previous", "Search logs of the previous container instance").
+				DataType("boolean")).
+			Returns(200, "OK", logs.LogSearchResult{}).
+			Returns(400, "Bad Request", errors.StatusErrorResponse{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Doc("Search the container logs. Id of each match can be used as the reference line to read lines "+
+				"around it").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.LogDocsTag}))
+	ws.Route(
+		ws.GET(prefix+"/log/file/{namespace}/{pod}/{container}").
+			To(apiHandler.handleGetLogFile).
+			Produces("application/octet-stream").
+			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
+			Param(ws.PathParameter("pod", "Name of Pod").Required(true)).
+			Param(ws.PathParameter("container", "Name of the container").Required(true)).
+			Param(ws.QueryParameter("previous", "Return logs of the previous container instance").
+				DataType("boolean")).
+			Returns(200, "OK", nil).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Doc("Download the whole container logs").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.LogDocsTag}))
+	ws.Route(
+		ws.GET(prefix+"/log/follow/{namespace}/{pod}/{container}").
+			To(apiHandler.handleFollowLogs).
+			Produces(eventStreamMIME).
+			// Compressed responses are buffered, events have to reach the client right away.
+			ContentEncodingEnabled(false).
+			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
+			Param(ws.PathParameter("pod", "Name of Pod").Required(true)).
+			Param(ws.PathParameter("container", "Name of the container").Required(true)).
+			Param(ws.QueryParameter("tailLines",
+				"Number of existing lines sent before the new ones, defaults to "+
+					strconv.FormatInt(container.DefaultFollowTailLines, 10)).DataType("int")).
+			Param(ws.QueryParameter(kubernetes.URLTokenParameter,
+				"Token returned by the token route, for clients that can not set headers `e.g. EventSource`")).
+			Returns(200, "OK", logs.LogLine{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Doc("Follow the container logs as Server-Sent Events. Every line is sent as a log event, end event is "+
+				"sent when the container terminates and error event when reading of the logs fails. Browsers "+
+				"pass the credentials in the token query parameter").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.LogDocsTag}))
+	ws.Route(
+		ws.GET(prefix+"/log/follow/{namespace}/{pod}/{container}/token").
+			To(apiHandler.handleGetFollowLogsToken).
+			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
+			Param(ws.PathParameter("pod", "Name of Pod").Required(true)).
+			Param(ws.PathParameter("container", "Name of the container").Required(true)).
+			Returns(200, "OK", k8sApi.URLTokenResponse{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Doc(fmt.Sprintf("Issue token that authenticates only following of the container logs, passed in the "+
+				"token query parameter by clients that can not set headers `e.g. EventSource`. The stream has to "+
+				"be opened within %s", kubernetes.URLTokenTTL)).
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.LogDocsTag}))
+}
+
+func (apiHandler *APIHandler) logRoute(ws *restful.WebService, path string) *restful.RouteBuilder {
+	return logSelectionParams(ws, ws.GET(path).
+		To(apiHandler.handleGetLogs).
+		Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
+		Param(ws.PathParameter("pod", "Name of Pod").Required(true))).
+		Returns(200, "OK", logs.LogDetails{}).
+		Returns(400, "Bad Request", errors.StatusErrorResponse{}).
+		Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+		Doc("Read the selected lines of the container logs. First container of the Pod is used when "+
+			"container is not given").
+		Metadata(restfulspec.KeyOpenAPITags, []string{docs.LogDocsTag})
+}
+
+func logSelectionParams(ws *restful.WebService, builder *restful.RouteBuilder) *restful.RouteBuilder {
+	return builder.
+		Param(ws.QueryParameter("referenceTimestamp",
+			"Timestamp of the line offsets are relative to, "+logs.NewestTimestamp+" or "+logs.OldestTimestamp)).
+		Param(ws.QueryParameter("referenceLineNum",
+			"Number of the line offsets are relative to, among lines with the reference timestamp").
+			DataType("int")).
+		Param(ws.QueryParameter("offsetFrom", "First line to return, relative to the reference line").
+			DataType("int")).
+		Param(ws.QueryParameter("offsetTo", "Line after the last one to return, relative to the reference line").
+			DataType("int")).
+		Param(ws.QueryParameter("logFilePosition",
+			"Read logs from the "+logs.Beginning+" or the "+logs.End+" of the log file")).
+		Param(ws.QueryParameter("previous", "Return logs of the previous container instance").
+			DataType("boolean")).
+		Param(ws.QueryParameter("structured", "Parse level, message and fields of JSON and logfmt lines").
+			DataType("boolean")).
+		Param(ws.QueryParameter("level", "Comma separated levels of the lines to return, enables parsing")).
+		Param(ws.QueryParameter("field", "Field of the lines to return given as key=value, can be repeated, "+
+			"enables parsing").AllowMultiple(true))
+}
+
+func (apiHandler *APIHandler) handleGetLogSource(request *restful.Request, response *restful.Response) {
+	k8s, err := apiHandler.kManager.Kubernetes(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	resourceName := request.PathParameter("resourceName")
+	resourceType := request.PathParameter("resourceType")
+	result, err := logs.GetLogSources(k8s, namespace, resourceName, resourceType)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetLogs(request *restful.Request, response *restful.Response) {
+	k8s, err := apiHandler.kManager.Kubernetes(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	pod := request.PathParameter("pod")
+	containerName := request.PathParameter("container")
+	previous := request.QueryParameter("previous") == "true"
+	logSelection := parser.ParseLogSelectionQueryParameter(request)
+	structured, err := parser.ParseStructuredLogQueryParameter(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := container.GetLogDetail(k8s, namespace, pod, containerName, logSelection, structured, previous)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetAggregatedLogs(request *restful.Request, response *restful.Response) {
+	k8s, err := apiHandler.kManager.Kubernetes(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	resourceName := request.PathParameter("resourceName")
+	resourceType := request.PathParameter("resourceType")
+	previous := request.QueryParameter("previous") == "true"
+	logSelection := parser.ParseLogSelectionQueryParameter(request)
+	structured, err := parser.ParseStructuredLogQueryParameter(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	result, err := container.GetAggregatedLogDetail(k8s, namespace, resourceName, resourceType, logSelection,
+		structured, previous)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleSearchLogs(request *restful.Request, response *restful.Response) {
+	k8s, err := apiHandler.kManager.Kubernetes(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	query, err := parser.ParseLogSearchQueryParameter(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	pod := request.PathParameter("pod")
+	containerName := request.PathParameter("container")
+	logFilePosition := request.QueryParameter("logFilePosition")
+	previous := request.QueryParameter("previous") == "true"
+	result, err := container.SearchLogs(k8s, namespace, pod, containerName, query, logFilePosition, previous)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handleGetLogFile(request *restful.Request, response *restful.Response) {
+	k8s, err := apiHandler.kManager.Kubernetes(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	pod := request.PathParameter("pod")
+	containerName := request.PathParameter("container")
+	previous := request.QueryParameter("previous") == "true"
+	logStream, err := container.GetLogFile(k8s, namespace, pod, containerName, previous)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	defer logStream.Close()
+
+	response.AddHeader("Content-Type", "application/octet-stream")
+	response.AddHeader("Content-Disposition", fmt.Sprintf("attachment; filename=%q",
+		fmt.Sprintf("logs-from-%s-in-%s.txt", containerName, pod)))
+	response.WriteHeader(http.StatusOK)
+	// Headers are already sent, so errors in the middle of the stream can only be logged.
+	if _, err = io.Copy(response, logStream); err != nil {
+		log.Printf("Could not stream logs of container %s in pod %s. Reason: %s", containerName, pod, err)
+	}
+}
+
+// handleGetFollowLogsToken issues URL token for the follow route the token route is nested under.
+func (apiHandler *APIHandler) handleGetFollowLogsToken(request *restful.Request, response *restful.Response) {
+	path := strings.TrimSuffix(request.Request.URL.Path, "/token")
+	token, err := apiHandler.kManager.URLToken(request, path)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, k8sApi.URLTokenResponse{Token: token})
+}
+
+func (apiHandler *APIHandler) handleFollowLogs(request *restful.Request, response *restful.Response) {
+	k8s, err := apiHandler.kManager.Kubernetes(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	namespace := request.PathParameter("namespace")
+	pod := request.PathParameter("pod")
+	containerName := request.PathParameter("container")
+	tailLines, err := strconv.ParseInt(request.QueryParameter("tailLines"), 10, 64)
+	if err != nil || tailLines < 0 {
+		tailLines = container.DefaultFollowTailLines
+	}
+
+	writer := newSSEWriter(response)
+	stop := make(chan struct{})
+	var keepAlive sync.WaitGroup
+	keepAlive.Add(1)
+	go func() {
+		defer keepAlive.Done()
+		writer.keepAlive(stop, sseKeepAliveInterval)
+	}()
+
+	// Context of the request is done when the client disconnects, which closes the log stream.
+	ctx := request.Request.Context()
+	options := container.FollowOptions{
+		TailLines:         tailLines,
+		MaxLinesPerSecond: args.Holder.GetLogStreamMaxLinesPerSecond(),
+	}
+	err = container.FollowLogs(ctx, k8s, namespace, pod, containerName, options, func(line logs.LogLine) error {
+		return writer.event("log", line)
+	})
+	close(stop)
+	keepAlive.Wait()
+
+	switch {
+	case ctx.Err() != nil:
+		return
+	case err != nil && !writer.isStarted():
+		errors.HandleInternalError(response, err)
+	case err != nil:
+		log.Printf("Stopped following logs of container %s in pod %s. Reason: %s", containerName, pod, err)
+		writer.event("error", errors.StatusErrorResponse{Message: err.Error()})
+	default:
+		writer.event("end", nil)
+	}
+}
@@ -16,16 +16,16 @@ import (
 	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/pod"
 )
 
-func (apiHandler *APIHandler) installNode(ws *restful.WebService) {
+func (apiHandler *APIHandler) installNode(ws *restful.WebService, prefix string) {
 	ws.Route(
-		ws.GET("/node").
+		ws.GET(prefix+"/node").
 			To(apiHandler.handleGetNodeList).
 			Returns(200, "OK", node.NodeList{}).
 			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
 			Doc("List objects of kind Node").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.NodeDocsTag}))
 	ws.Route(
-		ws.GET("/node/{name}").
+		ws.GET(prefix+"/node/{name}").
 			To(apiHandler.handleGetNodeDetail).
 			Param(ws.PathParameter("name", "Name of Node").Required(true)).
 			Returns(200, "OK", node.NodeDetail{}).
@@ -33,7 +33,7 @@ func (apiHandler *APIHandler) installNode(ws *restful.WebService) {
 			Doc("Read the specified Node").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.NodeDocsTag}))
 	ws.Route(
-		ws.GET("/node/{name}/event").
+		ws.GET(prefix+"/node/{name}/event").
 			To(apiHandler.handleGetNodeEvents).
 			Param(ws.PathParameter("name", "Name of Node").Required(true)).
 			Returns(200, "OK", common.EventList{}).
@@ -41,7 +41,7 @@ func (apiHandler *APIHandler) installNode(ws *restful.WebService) {
 			Doc("List events related to a Node").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.NodeDocsTag}))
 	ws.Route(
-		ws.GET("/node/{name}/pod").
+		ws.GET(prefix+"/node/{name}/pod").
 			To(apiHandler.handleGetNodePods).
 			Param(ws.PathParameter("name", "Name of Node").Required(true)).
 			Returns(200, "OK", pod.PodList{}).
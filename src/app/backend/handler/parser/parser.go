@@ -1,11 +1,14 @@
 package parser
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
 	metricApi "github.com/donghoon-khan/kubeportal/src/app/backend/integration/metric/api"
 	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/dataselect"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/logs"
 	"github.com/emicklei/go-restful/v3"
 )
 
@@ -60,3 +63,62 @@ func ParseDataSelectPathParameter(request *restful.Request) *dataselect.DataSele
 	metricQuery := parseMetricPathParameter(request)
 	return dataselect.NewDataSelectQuery(paginationQuery, sortQuery, filterQuery, metricQuery)
 }
+
+// ParseLogSelectionQueryParameter returns the part of the logs selected with the query parameters. Default
+// selection, i.e. the newest lines, is returned when offsets are not given.
+func ParseLogSelectionQueryParameter(request *restful.Request) *logs.Selection {
+	offsetFrom, errFrom := strconv.Atoi(request.QueryParameter("offsetFrom"))
+	offsetTo, errTo := strconv.Atoi(request.QueryParameter("offsetTo"))
+	if errFrom != nil || errTo != nil {
+		return logs.DefaultSelection
+	}
+
+	referenceTimestamp := request.QueryParameter("referenceTimestamp")
+	if len(referenceTimestamp) == 0 {
+		referenceTimestamp = logs.NewestTimestamp
+	}
+
+	referenceLineNum, err := strconv.Atoi(request.QueryParameter("referenceLineNum"))
+	if err != nil {
+		referenceLineNum = 0
+	}
+
+	return &logs.Selection{
+		ReferencePoint: logs.LogLineId{
+			LogTimestamp: logs.LogTimestamp(referenceTimestamp),
+			LineNum:      referenceLineNum,
+		},
+		OffsetFrom:      offsetFrom,
+		OffsetTo:        offsetTo,
+		LogFilePosition: request.QueryParameter("logFilePosition"),
+	}
+}
+
+// ParseLogSearchQueryParameter returns the search query given with the query parameters.
+func ParseLogSearchQueryParameter(request *restful.Request) (*logs.SearchQuery, error) {
+	contextLines := logs.DefaultSearchContextLines
+	if value := request.QueryParameter("contextLines"); len(value) > 0 {
+		var err error
+		if contextLines, err = strconv.Atoi(value); err != nil {
+			return nil, errors.NewBadRequest(fmt.Sprintf("invalid number of context lines: %s", value))
+		}
+	}
+
+	return logs.NewSearchQuery(request.QueryParameter("query"), request.QueryParameter("regex") == "true",
+		request.QueryParameter("ignoreCase") == "true", contextLines)
+}
+
+// ParseStructuredLogQueryParameter returns the query for structured parsing of logs, or nil when structured parsing
+// is not asked for. Filtering by level or field enables parsing too.
+func ParseStructuredLogQueryParameter(request *restful.Request) (*logs.StructuredQuery, error) {
+	var levels []string
+	if level := request.QueryParameter("level"); len(level) > 0 {
+		levels = strings.Split(level, ",")
+	}
+	fields := request.QueryParameters("field")
+	if request.QueryParameter("structured") != "true" && len(levels) == 0 && len(fields) == 0 {
+		return nil, nil
+	}
+
+	return logs.NewStructuredQuery(levels, fields)
+}
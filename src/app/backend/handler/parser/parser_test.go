@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/emicklei/go-restful/v3"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/logs"
+)
+
+func TestParseLogSelectionQueryParameter(t *testing.T) {
+	cases := []struct {
+		query    string
+		expected *logs.Selection
+	}{
+		{"", logs.DefaultSelection},
+		{"offsetFrom=10", logs.DefaultSelection},
+		{
+			"offsetFrom=-50&offsetTo=50&referenceTimestamp=2021-01-01T00:00:00Z&referenceLineNum=2" +
+				"&logFilePosition=beginning",
+			&logs.Selection{
+				ReferencePoint:  logs.LogLineId{LogTimestamp: "2021-01-01T00:00:00Z", LineNum: 2},
+				OffsetFrom:      -50,
+				OffsetTo:        50,
+				LogFilePosition: logs.Beginning,
+			},
+		},
+		{
+			"offsetFrom=0&offsetTo=100",
+			&logs.Selection{
+				ReferencePoint: logs.LogLineId{LogTimestamp: logs.NewestTimestamp},
+				OffsetTo:       100,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		httpRequest, _ := http.NewRequest(http.MethodGet, "/api/v1/kubernetes/log/default/pod?"+c.query, nil)
+		actual := ParseLogSelectionQueryParameter(restful.NewRequest(httpRequest))
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("ParseLogSelectionQueryParameter(%s): expected %+v but got %+v", c.query, c.expected, actual)
+		}
+	}
+}
+
+func TestParseLogSearchQueryParameter(t *testing.T) {
+	cases := []struct {
+		query         string
+		expectedLines int
+		valid         bool
+	}{
+		{"query=error", logs.DefaultSearchContextLines, true},
+		{"query=error&contextLines=10", 10, true},
+		{"query=error&contextLines=1000", logs.MaxSearchContextLines, true},
+		{"query=error&contextLines=ten", 0, false},
+		{"query=(&regex=true", 0, false},
+		{"", 0, false},
+	}
+
+	for _, c := range cases {
+		httpRequest, _ := http.NewRequest(http.MethodGet, "/api/v1/kubernetes/log/search/default/pod/app?"+c.query,
+			nil)
+		actual, err := ParseLogSearchQueryParameter(restful.NewRequest(httpRequest))
+		if !c.valid {
+			if err == nil {
+				t.Errorf("ParseLogSearchQueryParameter(%s): expected error but got none", c.query)
+			}
+			continue
+		}
+
+		if err != nil || actual.ContextLines != c.expectedLines {
+			t.Errorf("ParseLogSearchQueryParameter(%s): expected %d context lines but got %+v, %v", c.query,
+				c.expectedLines, actual, err)
+		}
+	}
+}
+
+func TestParseStructuredLogQueryParameter(t *testing.T) {
+	cases := []struct {
+		query    string
+		expected *logs.StructuredQuery
+	}{
+		{"", nil},
+		{"structured=false", nil},
+		{"structured=true", &logs.StructuredQuery{Levels: []string{}, Fields: map[string]string{}}},
+		{
+			"level=error,warning&field=path%3D%2Fapi&field=method%3DGET",
+			&logs.StructuredQuery{
+				Levels: []string{"error", "warn"},
+				Fields: map[string]string{"path": "/api", "method": "GET"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		httpRequest, _ := http.NewRequest(http.MethodGet, "/api/v1/kubernetes/log/default/pod?"+c.query, nil)
+		actual, err := ParseStructuredLogQueryParameter(restful.NewRequest(httpRequest))
+		if err != nil || !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("ParseStructuredLogQueryParameter(%s): expected %+v but got %+v, %v", c.query, c.expected,
+				actual, err)
+		}
+	}
+}
@@ -13,16 +13,16 @@ import (
 	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/persistentvolumeclaim"
 )
 
-func (apiHandler *APIHandler) installPersistentVolumeClaim(ws *restful.WebService) {
+func (apiHandler *APIHandler) installPersistentVolumeClaim(ws *restful.WebService, prefix string) {
 	ws.Route(
-		ws.GET("/persistentvolumeclaim").
+		ws.GET(prefix+"/persistentvolumeclaim").
 			To(apiHandler.handleGetPersistentVolumeClaimList).
 			Returns(200, "OK", persistentvolumeclaim.PersistentVolumeClaimList{}).
 			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
 			Doc("List objects of kind PersistentVolumeClaim").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.PersistentVolumeClaimDocsTag}))
 	ws.Route(
-		ws.GET("/persistentvolumeclaim/{namespace}").
+		ws.GET(prefix+"/persistentvolumeclaim/{namespace}").
 			To(apiHandler.handleGetPersistentVolumeClaimListNamespace).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Returns(200, "OK", persistentvolumeclaim.PersistentVolumeClaimList{}).
@@ -30,7 +30,7 @@ func (apiHandler *APIHandler) installPersistentVolumeClaim(ws *restful.WebServic
 			Doc("List objects of kind PersistentVolumeClaim in the Namespace").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.PersistentVolumeClaimDocsTag}))
 	ws.Route(
-		ws.GET("/persistentvolumeclaim/{namespace}/{name}").
+		ws.GET(prefix+"/persistentvolumeclaim/{namespace}/{name}").
 			To(apiHandler.handleGetPersistentVolumeClaimDetail).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Param(ws.PathParameter("name", "Name of PersistentVolumeClaim").Required(true)).
@@ -16,16 +16,16 @@ import (
 	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/pod"
 )
 
-func (apiHandler *APIHandler) installPod(ws *restful.WebService) {
+func (apiHandler *APIHandler) installPod(ws *restful.WebService, prefix string) {
 	ws.Route(
-		ws.GET("/pod").
+		ws.GET(prefix+"/pod").
 			To(apiHandler.handleGetPodList).
 			Returns(200, "OK", pod.PodList{}).
 			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
 			Doc("List objects of kind Pod").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.PodDocsTag}))
 	ws.Route(
-		ws.GET("/pod/{namespace}").
+		ws.GET(prefix+"/pod/{namespace}").
 			To(apiHandler.handleGetPodListNamespace).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Returns(200, "OK", pod.PodList{}).
@@ -33,7 +33,7 @@ func (apiHandler *APIHandler) installPod(ws *restful.WebService) {
 			Doc("List objects of kind Pod in the Namespace").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.PodDocsTag}))
 	ws.Route(
-		ws.GET("/pod/{namespace}/{name}").
+		ws.GET(prefix+"/pod/{namespace}/{name}").
 			To(apiHandler.handleGetPodDetail).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Param(ws.PathParameter("name", "Name of Pod").Required(true)).
@@ -42,7 +42,7 @@ func (apiHandler *APIHandler) installPod(ws *restful.WebService) {
 			Doc("Read the specified Pod").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.PodDocsTag}))
 	ws.Route(
-		ws.GET("/pod/{namespace}/{name}/container").
+		ws.GET(prefix+"/pod/{namespace}/{name}/container").
 			To(apiHandler.handleGetPodContainerList).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Param(ws.PathParameter("name", "Name of Pod").Required(true)).
@@ -51,7 +51,7 @@ func (apiHandler *APIHandler) installPod(ws *restful.WebService) {
 			Doc("List containers related to a Pod").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.PodDocsTag}))
 	ws.Route(
-		ws.GET("/pod/{namespace}/{name}/event").
+		ws.GET(prefix+"/pod/{namespace}/{name}/event").
 			To(apiHandler.handleGetPodEvents).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Param(ws.PathParameter("name", "Name of Pod").Required(true)).
@@ -60,7 +60,7 @@ func (apiHandler *APIHandler) installPod(ws *restful.WebService) {
 			Doc("List events related to a Pod").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.PodDocsTag}))
 	ws.Route(
-		ws.GET("/pod/{namespace}/{name}/persistentvolumeclaim").
+		ws.GET(prefix+"/pod/{namespace}/{name}/persistentvolumeclaim").
 			To(apiHandler.handleGetPodPersistentVolumeClaims).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Param(ws.PathParameter("name", "Name of Pod").Required(true)).
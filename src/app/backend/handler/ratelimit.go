@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emicklei/go-restful/v3"
+
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+)
+
+const (
+	retryAfterHeader = "Retry-After"
+	// Login requests are small. Larger bodies are rejected before the limiter is checked, so that unauthenticated
+	// clients can not make the server buffer them.
+	maxLoginBodySize = 64 * 1024
+)
+
+// loginLimits configures how many failed logins are tolerated before clients are locked out. Zero maximum
+// disables the limit of its kind.
+type loginLimits struct {
+	maxAttemptsPerUser int
+	maxAttemptsPerIP   int
+	// backoff is the time clients have to wait after the first failed login. It doubles with every next failure.
+	backoff time.Duration
+	// lockout is the time clients have to wait after reaching the maximum. Failures older than lockout are
+	// forgotten.
+	lockout time.Duration
+}
+
+type loginAttempts struct {
+	failures     int
+	lastFailure  time.Time
+	blockedUntil time.Time
+}
+
+// loginLimiter counts failed logins per client IP and per user name and tells for how long the next attempt has to
+// be delayed.
+type loginLimiter struct {
+	mux      sync.Mutex
+	limits   loginLimits
+	attempts map[string]*loginAttempts
+	now      func() time.Time
+}
+
+// reserve returns time the client has to wait before trying to log in again. When the client can try now, the
+// attempt is counted as failed right away and forgotten only when the login succeeds, so that parallel attempts can
+// not get past the limits.
+func (self *loginLimiter) reserve(keys map[string]int) time.Duration {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	now := self.now()
+	result := time.Duration(0)
+	for key := range keys {
+		if attempts, exists := self.attempts[key]; exists && attempts.blockedUntil.After(now) {
+			if wait := attempts.blockedUntil.Sub(now); wait > result {
+				result = wait
+			}
+		}
+	}
+
+	if result > 0 {
+		return result
+	}
+
+	self.prune(now)
+	for key, maxAttempts := range keys {
+		attempts, exists := self.attempts[key]
+		if !exists {
+			attempts = &loginAttempts{}
+			self.attempts[key] = attempts
+		}
+
+		attempts.failures++
+		attempts.lastFailure = now
+		attempts.blockedUntil = now.Add(self.delay(attempts.failures, maxAttempts))
+	}
+
+	return 0
+}
+
+func (self *loginLimiter) success(keys ...string) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	for _, key := range keys {
+		delete(self.attempts, key)
+	}
+}
+
+func (self *loginLimiter) delay(failures, maxAttempts int) time.Duration {
+	if failures >= maxAttempts {
+		return self.limits.lockout
+	}
+
+	delay := float64(self.limits.backoff) * math.Pow(2, float64(failures-1))
+	if delay > float64(self.limits.lockout) {
+		return self.limits.lockout
+	}
+
+	return time.Duration(delay)
+}
+
+func (self *loginLimiter) prune(now time.Time) {
+	for key, attempts := range self.attempts {
+		if now.Sub(attempts.lastFailure) > self.limits.lockout && !attempts.blockedUntil.After(now) {
+			delete(self.attempts, key)
+		}
+	}
+}
+
+// keys returns limiter keys of the login request together with the maximum of failures allowed for them.
+func (self *loginLimiter) keys(clientIP, username string) map[string]int {
+	result := map[string]int{}
+	if self.limits.maxAttemptsPerIP > 0 {
+		result["ip:"+clientIP] = self.limits.maxAttemptsPerIP
+	}
+
+	if self.limits.maxAttemptsPerUser > 0 && len(username) > 0 {
+		result["user:"+strings.ToLower(username)] = self.limits.maxAttemptsPerUser
+	}
+
+	return result
+}
+
+// loginRateLimitFilter rejects login requests of clients that failed to log in too many times recently. Outcome of
+// the login is reported by the login handler with the authApi.LoginSucceededAttribute request attribute.
+func loginRateLimitFilter(limiter *loginLimiter, rootPath string) restful.FilterFunction {
+	return func(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+		if request.Request.Method != http.MethodPost ||
+			mapRouteToAction(request.SelectedRoutePath(), rootPath) != "login" {
+			chain.ProcessFilter(request, response)
+			return
+		}
+
+		username, err := readLoginUsername(request)
+		if err != nil {
+			errors.HandleInternalError(response, err)
+			return
+		}
+
+		keys := limiter.keys(getRemoteIP(request.Request), username)
+		if wait := limiter.reserve(keys); wait > 0 {
+			err := errors.NewTooManyRequests(errors.MsgLoginTooManyAttemptsError)
+			response.AddHeader(retryAfterHeader, fmt.Sprintf("%d", int(math.Ceil(wait.Seconds()))))
+			response.WriteHeaderAndEntity(int(err.ErrStatus.Code), errors.StatusErrorResponse{Message: err.Error()})
+			return
+		}
+
+		chain.ProcessFilter(request, response)
+
+		if succeeded, _ := request.Attribute(authApi.LoginSucceededAttribute).(bool); succeeded {
+			keyNames := make([]string, 0, len(keys))
+			for key := range keys {
+				keyNames = append(keyNames, key)
+			}
+
+			limiter.success(keyNames...)
+		}
+	}
+}
+
+// getRemoteIP returns address of the client without the port, which changes with every connection.
+func getRemoteIP(r *http.Request) string {
+	addr := getRemoteAddr(r)
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return addr
+}
+
+// readLoginUsername returns user name of the basic login request and leaves the body to be read again by the
+// login handler. Bodies larger than maxLoginBodySize are rejected.
+func readLoginUsername(request *restful.Request) (string, error) {
+	if request.Request.Body == nil {
+		return "", nil
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(request.Request.Body, maxLoginBodySize+1))
+	if err != nil {
+		return "", errors.NewBadRequest(err.Error())
+	}
+
+	if len(body) > maxLoginBodySize {
+		return "", errors.NewRequestEntityTooLarge(
+			fmt.Sprintf("login request body can not be larger than %d bytes", maxLoginBodySize))
+	}
+
+	request.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	spec := authApi.LoginSpec{}
+	if err = json.Unmarshal(body, &spec); err != nil {
+		return "", nil
+	}
+
+	return spec.Username, nil
+}
+
+func newLoginLimiter(limits loginLimits) *loginLimiter {
+	return &loginLimiter{
+		limits:   limits,
+		attempts: map[string]*loginAttempts{},
+		now:      time.Now,
+	}
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"net/http"
+
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/docs"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+)
+
+func (apiHandler *APIHandler) installRaw(ws *restful.WebService, prefix string) {
+	apiHandler.installRawRoutes(ws, prefix+"/_raw/{kind}/namespace/{namespace}/name/{name}", true)
+	// Cluster scoped resources, e.g. nodes.
+	apiHandler.installRawRoutes(ws, prefix+"/_raw/{kind}/name/{name}", false)
+}
+
+func (apiHandler *APIHandler) installRawRoutes(ws *restful.WebService, path string, namespaced bool) {
+	params := []*restful.Parameter{ws.PathParameter("kind", "Kind of the resource `e.g. deployment`").Required(true)}
+	if namespaced {
+		params = append(params, ws.PathParameter("namespace", "Namespace of the resource").Required(true))
+	}
+	params = append(params, ws.PathParameter("name", "Name of the resource").Required(true))
+
+	get := ws.GET(path).
+		To(apiHandler.handleGetResource).
+		Writes(runtime.Unknown{}).
+		Returns(200, "OK", runtime.Unknown{}).
+		Returns(400, "Bad Request", errors.StatusErrorResponse{}).
+		Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+		Returns(404, "Not Found", errors.StatusErrorResponse{}).
+		Doc("Read the specified resource as returned by the API server").
+		Metadata(restfulspec.KeyOpenAPITags, []string{docs.RawDocsTag})
+	put := ws.PUT(path).
+		To(apiHandler.handlePutResource).
+		Reads(runtime.Unknown{}).
+		Returns(200, "OK", nil).
+		Returns(400, "Bad Request", errors.StatusErrorResponse{}).
+		Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+		Returns(409, "Conflict", errors.StatusErrorResponse{}).
+		Doc("Replace the specified resource").
+		Metadata(restfulspec.KeyOpenAPITags, []string{docs.RawDocsTag})
+	del := ws.DELETE(path).
+		To(apiHandler.handleDeleteResource).
+		Returns(200, "OK", nil).
+		Returns(400, "Bad Request", errors.StatusErrorResponse{}).
+		Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+		Returns(404, "Not Found", errors.StatusErrorResponse{}).
+		Doc("Delete the specified resource together with its dependents").
+		Metadata(restfulspec.KeyOpenAPITags, []string{docs.RawDocsTag})
+
+	for _, route := range []*restful.RouteBuilder{get, put, del} {
+		for _, param := range params {
+			route.Param(param)
+		}
+		ws.Route(route)
+	}
+}
+
+func (apiHandler *APIHandler) handleGetResource(request *restful.Request, response *restful.Response) {
+	verber, err := apiHandler.kManager.VerberClient(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	kind := request.PathParameter("kind")
+	namespace, namespaceSet := request.PathParameters()["namespace"]
+	name := request.PathParameter("name")
+	result, err := verber.Get(kind, namespaceSet, namespace, name)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+func (apiHandler *APIHandler) handlePutResource(request *restful.Request, response *restful.Response) {
+	verber, err := apiHandler.kManager.VerberClient(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	object := new(runtime.Unknown)
+	if err := request.ReadEntity(object); err != nil {
+		errors.HandleInternalError(response, errors.NewBadRequest(err.Error()))
+		return
+	}
+
+	kind := request.PathParameter("kind")
+	namespace, namespaceSet := request.PathParameters()["namespace"]
+	name := request.PathParameter("name")
+	if err := verber.Put(kind, namespaceSet, namespace, name, object); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeader(http.StatusOK)
+}
+
+func (apiHandler *APIHandler) handleDeleteResource(request *restful.Request, response *restful.Response) {
+	verber, err := apiHandler.kManager.VerberClient(request)
+	if err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	kind := request.PathParameter("kind")
+	namespace, namespaceSet := request.PathParameters()["namespace"]
+	name := request.PathParameter("name")
+	if err := verber.Delete(kind, namespaceSet, namespace, name); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+	response.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// NewHTTPSRedirectHandler creates handler that redirects every request to the same URL served over HTTPS on the
+// given port. Permanent redirect keeps the method and body of the request.
+func NewHTTPSRedirectHandler(securePort int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+
+		if securePort != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(securePort))
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+
+		target := url.URL{Scheme: "https", Host: host, Path: r.URL.Path, RawQuery: r.URL.RawQuery}
+		http.Redirect(w, r, target.String(), http.StatusPermanentRedirect)
+	})
+}
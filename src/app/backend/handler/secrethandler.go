@@ -13,16 +13,16 @@ import (
 	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/secret"
 )
 
-func (apiHandler *APIHandler) installSecret(ws *restful.WebService) {
+func (apiHandler *APIHandler) installSecret(ws *restful.WebService, prefix string) {
 	ws.Route(
-		ws.GET("/secret").
+		ws.GET(prefix+"/secret").
 			To(apiHandler.handleGetSecretList).
 			Returns(200, "OK", secret.SecretList{}).
 			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
 			Doc("List objects of kind Secret").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.SecretDocsTag}))
 	ws.Route(
-		ws.GET("/secret/{namespace}").
+		ws.GET(prefix+"/secret/{namespace}").
 			To(apiHandler.handleGetSecretListNamespace).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Returns(200, "OK", secret.SecretList{}).
@@ -30,7 +30,7 @@ func (apiHandler *APIHandler) installSecret(ws *restful.WebService) {
 			Doc("List objects of kind Secret in the Namespace").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.SecretDocsTag}))
 	ws.Route(
-		ws.GET("/secret/{namespace}/{name}").
+		ws.GET(prefix+"/secret/{namespace}/{name}").
 			To(apiHandler.handleGetSecretDetail).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Param(ws.PathParameter("name", "Name of Secret").Required(true)).
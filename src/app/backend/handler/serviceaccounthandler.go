@@ -14,16 +14,16 @@ import (
 	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/serviceaccount"
 )
 
-func (apiHandler *APIHandler) installServiceAccount(ws *restful.WebService) {
+func (apiHandler *APIHandler) installServiceAccount(ws *restful.WebService, prefix string) {
 	ws.Route(
-		ws.GET("/serviceaccount").
+		ws.GET(prefix+"/serviceaccount").
 			To(apiHandler.handleGetServiceAccountList).
 			Returns(200, "OK", serviceaccount.ServiceAccountList{}).
 			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
 			Doc("List objects of kind ServiceAccount").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.ServiceAccountDocsTag}))
 	ws.Route(
-		ws.GET("/serviceaccount/{namespace}").
+		ws.GET(prefix+"/serviceaccount/{namespace}").
 			To(apiHandler.handleGetServiceAccountListNamespace).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Returns(200, "OK", serviceaccount.ServiceAccountList{}).
@@ -31,7 +31,7 @@ func (apiHandler *APIHandler) installServiceAccount(ws *restful.WebService) {
 			Doc("List objects of kind ServiceAccount in the Namespace").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.ServiceAccountDocsTag}))
 	ws.Route(
-		ws.GET("/serviceaccount/{namespace}/{name}").
+		ws.GET(prefix+"/serviceaccount/{namespace}/{name}").
 			To(apiHandler.handleGetServiceAccountDetail).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Param(ws.PathParameter("name", "Name of ServiceAccount").Required(true)).
@@ -40,7 +40,7 @@ func (apiHandler *APIHandler) installServiceAccount(ws *restful.WebService) {
 			Doc("Read the specified ServiceAccount").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.ServiceAccountDocsTag}))
 	ws.Route(
-		ws.GET("/serviceaccount/{namespace}/{name}/secret").
+		ws.GET(prefix+"/serviceaccount/{namespace}/{name}/secret").
 			To(apiHandler.handleGetServiceAccountSecrets).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Param(ws.PathParameter("name", "Name of ServiceAccount").Required(true)).
@@ -49,7 +49,7 @@ func (apiHandler *APIHandler) installServiceAccount(ws *restful.WebService) {
 			Doc("List Secrets related to a ServiceAccount").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.ServiceAccountDocsTag}))
 	ws.Route(
-		ws.GET("/serviceaccount/{namespace}/{name}/imagepullsecret").
+		ws.GET(prefix+"/serviceaccount/{namespace}/{name}/imagepullsecret").
 			To(apiHandler.handleGetServiceAccountImagePullSecrets).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Param(ws.PathParameter("name", "Name of ServiceAccount").Required(true)).
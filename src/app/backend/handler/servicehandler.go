@@ -15,16 +15,16 @@ import (
 	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/service"
 )
 
-func (apiHandler *APIHandler) installService(ws *restful.WebService) {
+func (apiHandler *APIHandler) installService(ws *restful.WebService, prefix string) {
 	ws.Route(
-		ws.GET("/service").
+		ws.GET(prefix+"/service").
 			To(apiHandler.handleGetServiceList).
 			Returns(200, "OK", service.ServiceList{}).
 			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
 			Doc("List objects of kind Service").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.ServiceDocsTag}))
 	ws.Route(
-		ws.GET("/service/{namespace}").
+		ws.GET(prefix+"/service/{namespace}").
 			To(apiHandler.handleGetServiceListNamespace).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Returns(200, "OK", service.ServiceList{}).
@@ -32,7 +32,7 @@ func (apiHandler *APIHandler) installService(ws *restful.WebService) {
 			Doc("List objects of kind Service in the Namespace").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.ServiceDocsTag}))
 	ws.Route(
-		ws.GET("/service/{namespace}/{name}").
+		ws.GET(prefix+"/service/{namespace}/{name}").
 			To(apiHandler.handleGetServiceDetail).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Param(ws.PathParameter("name", "Name of Service").Required(true)).
@@ -41,7 +41,7 @@ func (apiHandler *APIHandler) installService(ws *restful.WebService) {
 			Doc("Read the specified Service").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.ServiceDocsTag}))
 	ws.Route(
-		ws.GET("/service/{namespace}/{name}/event").
+		ws.GET(prefix+"/service/{namespace}/{name}/event").
 			To(apiHandler.handleGetServiceEvents).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Param(ws.PathParameter("name", "Name of Service").Required(true)).
@@ -50,7 +50,7 @@ func (apiHandler *APIHandler) installService(ws *restful.WebService) {
 			Doc("List events related to a Service").
 			Metadata(restfulspec.KeyOpenAPITags, []string{docs.ServiceDocsTag}))
 	ws.Route(
-		ws.GET("/service/{namespace}/{name}/pod").
+		ws.GET(prefix+"/service/{namespace}/{name}/pod").
 			To(apiHandler.handleGetServicePods).
 			Param(ws.PathParameter("namespace", "Query for Namespace").Required(true)).
 			Param(ws.PathParameter("name", "Name of Service").Required(true)).
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/emicklei/go-restful/v3"
+)
+
+const (
+	eventStreamMIME = "text/event-stream"
+	// Comments sent in this interval keep proxies from closing idle streams.
+	sseKeepAliveInterval = 15 * time.Second
+)
+
+// sseWriter writes Server-Sent Events. Headers are sent with the first event, so that errors occurring before
+// can still be returned as regular responses. Writes are serialized, so that keep alive comments can be sent from
+// another goroutine.
+type sseWriter struct {
+	mux      sync.Mutex
+	response *restful.Response
+	started  bool
+}
+
+func (self *sseWriter) event(name string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return self.write(fmt.Sprintf("event: %s\ndata: %s\n\n", name, payload))
+}
+
+func (self *sseWriter) comment(text string) error {
+	return self.write(fmt.Sprintf(": %s\n\n", text))
+}
+
+func (self *sseWriter) write(message string) error {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	if !self.started {
+		self.response.AddHeader("Content-Type", eventStreamMIME)
+		self.response.AddHeader("Cache-Control", "no-cache")
+		// Disables response buffering of nginx based proxies.
+		self.response.AddHeader("X-Accel-Buffering", "no")
+		self.response.WriteHeader(http.StatusOK)
+		self.started = true
+	}
+
+	if _, err := self.response.Write([]byte(message)); err != nil {
+		return err
+	}
+
+	self.response.Flush()
+	return nil
+}
+
+func (self *sseWriter) isStarted() bool {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	return self.started
+}
+
+// keepAlive sends comments until stop is closed. Comments are not sent before the first event.
+func (self *sseWriter) keepAlive(stop <-chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if self.isStarted() {
+				if err := self.comment("keep-alive"); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+func newSSEWriter(response *restful.Response) *sseWriter {
+	return &sseWriter{response: response}
+}
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net/http"
+
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/docs"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+	k8sApi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
+)
+
+func (apiHandler *APIHandler) installStats(ws *restful.WebService) {
+	ws.Route(
+		ws.GET("/clientcache").
+			To(apiHandler.handleGetClientCacheStats).
+			Returns(200, "OK", k8sApi.ClientCacheStats{}).
+			Returns(401, "Unauthorized", errors.StatusErrorResponse{}).
+			Doc("Get hits, misses, evictions and size of the cache of clients created with users' credentials").
+			Metadata(restfulspec.KeyOpenAPITags, []string{docs.StatsDocsTag}))
+}
+
+func (apiHandler *APIHandler) handleGetClientCacheStats(request *restful.Request, response *restful.Response) {
+	// Counters are not bound to any user, but only users able to use the portal can read them.
+	if _, err := apiHandler.kManager.Config(request); err != nil {
+		errors.HandleInternalError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusOK, apiHandler.kManager.ClientCacheStats())
+}
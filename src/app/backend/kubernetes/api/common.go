@@ -6,8 +6,24 @@ import (
 	"strings"
 
 	v1 "k8s.io/api/authorization/v1"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
 )
 
+var clientTypeToAPIGroup = map[api.ClientType]string{
+	api.ClientTypeDefault:             "",
+	api.ClientTypeExtensionClient:     "extensions",
+	api.ClientTypeAppsClient:          "apps",
+	api.ClientTypeBatchClient:         "batch",
+	api.ClientTypeBetaBatchClient:     "batch",
+	api.ClientTypeAutoscalingClient:   "autoscaling",
+	api.ClientTypeStorageClient:       "storage.k8s.io",
+	api.ClientTypeRbacClient:          "rbac.authorization.k8s.io",
+	api.ClientTypeAPIExtensionsClient: "apiextensions.k8s.io",
+	api.ClientTypeNetworkingClient:    "networking.k8s.io",
+}
+
 func ToSelfSubjectAccessReview(namespace, name, resource, verb string) *v1.SelfSubjectAccessReview {
 	return &v1.SelfSubjectAccessReview{
 		Spec: v1.SelfSubjectAccessReviewSpec{
@@ -21,6 +37,43 @@ func ToSelfSubjectAccessReview(namespace, name, resource, verb string) *v1.SelfS
 	}
 }
 
+// ToKindSelfSubjectAccessReview creates SelfSubjectAccessReview for the resource kind known by the portal. Unlike
+// ToSelfSubjectAccessReview it resolves API group and resource name of the kind, so that the review matches
+// RBAC rules of non-core resources. Namespace is ignored for cluster scoped kinds.
+func ToKindSelfSubjectAccessReview(namespace, name, kind, subresource, verb string) (*v1.SelfSubjectAccessReview,
+	error) {
+	mapping, ok := api.KindToAPIMapping[strings.ToLower(kind)]
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("unknown resource kind: %s", kind))
+	}
+
+	group, ok := clientTypeToAPIGroup[mapping.ClientType]
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("access review is not supported for kind: %s", kind))
+	}
+
+	if len(verb) == 0 {
+		return nil, errors.NewBadRequest("verb is required")
+	}
+
+	if !mapping.Namespaced {
+		namespace = ""
+	}
+
+	return &v1.SelfSubjectAccessReview{
+		Spec: v1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &v1.ResourceAttributes{
+				Namespace:   namespace,
+				Name:        name,
+				Group:       group,
+				Resource:    mapping.Resource,
+				Subresource: strings.ToLower(subresource),
+				Verb:        strings.ToLower(verb),
+			},
+		},
+	}, nil
+}
+
 func GenerateCSRFKey() string {
 	bytes := make([]byte, 256)
 	_, err := rand.Read(bytes)
@@ -30,3 +83,13 @@ func GenerateCSRFKey() string {
 
 	return string(bytes)
 }
+
+// TokenCluster returns name of the cluster tokens issued for the given cluster are bound to. The default cluster
+// is bound with an empty name, the same as tokens issued before they have been bound to clusters.
+func TokenCluster(name string) string {
+	if name == DefaultClusterName {
+		return ""
+	}
+
+	return name
+}
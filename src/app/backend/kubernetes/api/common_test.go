@@ -30,3 +30,44 @@ func TestToSelfSubjectAccessReview(t *testing.T) {
 		t.Fatalf("Expected to get %+v but got %+v", expected, got)
 	}
 }
+
+func TestToKindSelfSubjectAccessReview(t *testing.T) {
+	cases := []struct {
+		namespace, name, kind, subresource, verb string
+		expected                                 *v1.ResourceAttributes
+		expectedErr                              bool
+	}{
+		{
+			"default", "", "cronjob", "", "LIST",
+			&v1.ResourceAttributes{Namespace: "default", Group: "batch", Resource: "cronjobs", Verb: "list"},
+			false,
+		},
+		{
+			"default", "test-pod", "Pod", "log", "get",
+			&v1.ResourceAttributes{Namespace: "default", Name: "test-pod", Resource: "pods", Subresource: "log",
+				Verb: "get"},
+			false,
+		},
+		{
+			"default", "test-role", "clusterrole", "", "delete",
+			&v1.ResourceAttributes{Name: "test-role", Group: "rbac.authorization.k8s.io",
+				Resource: "clusterroles", Verb: "delete"},
+			false,
+		},
+		{"default", "", "unknown", "", "get", nil, true},
+		{"default", "", "pod", "", "", nil, true},
+	}
+
+	for _, c := range cases {
+		got, err := api.ToKindSelfSubjectAccessReview(c.namespace, c.name, c.kind, c.subresource, c.verb)
+		if (err != nil) != c.expectedErr {
+			t.Fatalf("ToKindSelfSubjectAccessReview(%s): Expected error to be %v but got: %v", c.kind,
+				c.expectedErr, err)
+		}
+
+		if err == nil && !reflect.DeepEqual(got.Spec.ResourceAttributes, c.expected) {
+			t.Errorf("ToKindSelfSubjectAccessReview(%s): Expected %+v but got %+v", c.kind, c.expected,
+				got.Spec.ResourceAttributes)
+		}
+	}
+}
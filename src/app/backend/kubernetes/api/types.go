@@ -1,16 +1,29 @@
 package api
 
 import (
+	"time"
+
 	"github.com/emicklei/go-restful/v3"
+	v1 "k8s.io/api/authorization/v1"
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
 )
 
 const (
-	CsrfTokenSecretName = "kube-portal-csrf"
+	CsrfTokenSecretName = authApi.CsrfTokenSecretName
 	CsrfTokenSecretData = "csrf"
+
+	// DefaultClusterName is the name of the cluster served by routes without the cluster path parameter.
+	DefaultClusterName   = "default"
+	ClusterPathParameter = "cluster"
 )
 
 type KubernetesManager interface {
@@ -20,16 +33,37 @@ type KubernetesManager interface {
 	APIExtensionsKubernetes(req *restful.Request) (apiextensionsclientset.Interface, error)
 	InsecureAPIExtensionsKubernetes() apiextensionsclientset.Interface
 
+	DynamicKubernetes(req *restful.Request) (dynamic.Interface, error)
+
+	// APIResources returns resources served by the cluster selected by the request, as found by the discovery.
+	APIResources(req *restful.Request) (*APIResourceList, error)
+	// APIResource returns a single resource served by the cluster selected by the request. Core resources have
+	// empty group.
+	APIResource(req *restful.Request, group, version, resource string) (*APIResource, error)
+
+	Clusters() []string
+	InsecureClusterKubernetes(cluster string) (kubernetes.Interface, error)
+
 	//PluginKubernetes(req *restful.Request) (pluginclientset.Interface, error)
 	//InsecurePluginKubernetes() pluginclientset.Interface
 
-	//CanI(req *restful.Request, saar *v1.SelfSubjectAccessReview) bool
-	//Config(req *restful.Request) (*rest.Config, error)
-	//ClientCmdConfig(req *restful.Request) (clientcmd.ClientConfig, error)
-	//CSRFKey() string
-	//HasAccess(authInfo api.AuthInfo) error
-	//VerberClient(req *restful.Request) (ResourceVerber, error)
-	//SetTokenManager(manager authApi.TokenManager)
+	CanI(req *restful.Request, ssar *v1.SelfSubjectAccessReview) bool
+	Config(req *restful.Request) (*rest.Config, error)
+	ClientCmdConfig(req *restful.Request) (clientcmd.ClientConfig, error)
+	CSRFKey() string
+	// HasAccess checks that credentials can reach the cluster of the given name, empty name selects the default
+	// cluster.
+	HasAccess(authInfo api.AuthInfo, cluster string) error
+	VerberClient(req *restful.Request) (ResourceVerber, error)
+	SetTokenManager(manager authApi.TokenManager)
+	SetPersonalAccessTokenManager(manager authApi.PersonalAccessTokenManager)
+	// ClientCacheStats returns counters of the cache of clients created with users' credentials.
+	ClientCacheStats() ClientCacheStats
+	// AuthInfo returns credentials of the request, nil when it does not carry any.
+	AuthInfo(req *restful.Request) (*api.AuthInfo, error)
+	// URLToken returns short lived token that passes credentials of the request in the query of the given URL
+	// path, for clients that can not set headers, e.g. browser EventSource.
+	URLToken(req *restful.Request, path string) (string, error)
 }
 
 type ResourceVerber interface {
@@ -39,10 +73,60 @@ type ResourceVerber interface {
 	Delete(kind string, namespaceSet bool, namespace string, name string) error
 }
 
+// APIResource describes a resource served by the cluster.
+type APIResource struct {
+	Group      string   `json:"group"`
+	Version    string   `json:"version"`
+	Resource   string   `json:"resource"`
+	Kind       string   `json:"kind"`
+	Namespaced bool     `json:"namespaced"`
+	Verbs      []string `json:"verbs"`
+}
+
+type APIResourceList struct {
+	Resources   []APIResource `json:"resources"`
+	RefreshedAt time.Time     `json:"refreshedAt"`
+	Errors      []error       `json:"errors"`
+}
+
+type ClientCacheStats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Size      int    `json:"size"`
+}
+
+// URLTokenResponse carries the token returned by KubernetesManager.URLToken.
+type URLTokenResponse struct {
+	Token string `json:"token"`
+}
+
 type CanIResponse struct {
 	Allowed bool `json:"allowed"`
 }
 
+// CanIAction describes a single action checked by the batch CanI request.
+type CanIAction struct {
+	Verb        string `json:"verb"`
+	Kind        string `json:"kind"`
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Subresource string `json:"subresource,omitempty"`
+}
+
+type CanIBatchSpec struct {
+	Actions []CanIAction `json:"actions"`
+}
+
+type CanIActionResponse struct {
+	CanIAction
+	Allowed bool `json:"allowed"`
+}
+
+type CanIBatchResponse struct {
+	Actions []CanIActionResponse `json:"actions"`
+}
+
 type CsrfTokenManager interface {
 	Token() string
 }
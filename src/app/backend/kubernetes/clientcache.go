@@ -0,0 +1,219 @@
+package kubernetes
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	kubernetesapi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
+)
+
+const (
+	// Maximal number of distinct credentials clients are cached for. Least recently used ones are evicted first.
+	clientCacheSize = 256
+	// Time after which cached clients are created again, even if they are used all the time.
+	clientCacheTTL = 10 * time.Minute
+)
+
+// cachedClients holds clients created for a single config. Clients are created on the first use.
+type cachedClients struct {
+	key       string
+	config    *rest.Config
+	createdAt time.Time
+
+	mux           sync.Mutex
+	kubernetes    kubernetes.Interface
+	apiExtensions apiextensionsclientset.Interface
+	dynamic       dynamic.Interface
+}
+
+// clientCache caches clients created with users' credentials, so that every request does not build new clients and
+// transports.
+type clientCache struct {
+	mux       sync.Mutex
+	entries   map[string]*list.Element
+	lru       *list.List
+	size      int
+	ttl       time.Duration
+	hits      uint64
+	misses    uint64
+	evictions uint64
+	now       func() time.Time
+}
+
+func (self *clientCache) Kubernetes(config *rest.Config) (kubernetes.Interface, error) {
+	clients := self.get(config)
+	if clients == nil {
+		return kubernetes.NewForConfig(config)
+	}
+
+	clients.mux.Lock()
+	defer clients.mux.Unlock()
+	if clients.kubernetes != nil {
+		self.count(&self.hits)
+		return clients.kubernetes, nil
+	}
+
+	self.count(&self.misses)
+	k8sClient, err := kubernetes.NewForConfig(clients.config)
+	if err != nil {
+		return nil, err
+	}
+
+	clients.kubernetes = k8sClient
+	return k8sClient, nil
+}
+
+func (self *clientCache) APIExtensions(config *rest.Config) (apiextensionsclientset.Interface, error) {
+	clients := self.get(config)
+	if clients == nil {
+		return apiextensionsclientset.NewForConfig(config)
+	}
+
+	clients.mux.Lock()
+	defer clients.mux.Unlock()
+	if clients.apiExtensions != nil {
+		self.count(&self.hits)
+		return clients.apiExtensions, nil
+	}
+
+	self.count(&self.misses)
+	apiExtensionsClient, err := apiextensionsclientset.NewForConfig(clients.config)
+	if err != nil {
+		return nil, err
+	}
+
+	clients.apiExtensions = apiExtensionsClient
+	return apiExtensionsClient, nil
+}
+
+func (self *clientCache) Dynamic(config *rest.Config) (dynamic.Interface, error) {
+	clients := self.get(config)
+	if clients == nil {
+		return dynamic.NewForConfig(config)
+	}
+
+	clients.mux.Lock()
+	defer clients.mux.Unlock()
+	if clients.dynamic != nil {
+		self.count(&self.hits)
+		return clients.dynamic, nil
+	}
+
+	self.count(&self.misses)
+	dynamicClient, err := dynamic.NewForConfig(clients.config)
+	if err != nil {
+		return nil, err
+	}
+
+	clients.dynamic = dynamicClient
+	return dynamicClient, nil
+}
+
+func (self *clientCache) Stats() kubernetesapi.ClientCacheStats {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	return kubernetesapi.ClientCacheStats{
+		Hits:      self.hits,
+		Misses:    self.misses,
+		Evictions: self.evictions,
+		Size:      self.lru.Len(),
+	}
+}
+
+// get returns clients of the config, creating an empty entry when there is none. Nil is returned for configs that
+// can not be cached.
+func (self *clientCache) get(config *rest.Config) *cachedClients {
+	key := clientCacheKey(config)
+	if len(key) == 0 {
+		return nil
+	}
+
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	now := self.now()
+	if element, exists := self.entries[key]; exists {
+		clients := element.Value.(*cachedClients)
+		if now.Sub(clients.createdAt) < self.ttl {
+			self.lru.MoveToFront(element)
+			return clients
+		}
+
+		self.remove(element)
+	}
+
+	clients := &cachedClients{key: key, config: config, createdAt: now}
+	self.entries[key] = self.lru.PushFront(clients)
+	for self.lru.Len() > self.size {
+		self.remove(self.lru.Back())
+	}
+
+	return clients
+}
+
+func (self *clientCache) remove(element *list.Element) {
+	self.lru.Remove(element)
+	delete(self.entries, element.Value.(*cachedClients).key)
+	self.evictions++
+}
+
+func (self *clientCache) count(counter *uint64) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+	*counter++
+}
+
+// clientCacheKey returns hash of everything that makes clients created for the config different. Configs with auth
+// or exec providers can refresh their credentials on their own and are not cached.
+func clientCacheKey(config *rest.Config) string {
+	if config.AuthProvider != nil || config.ExecProvider != nil || config.WrapTransport != nil ||
+		config.Transport != nil || config.Dial != nil {
+		return ""
+	}
+
+	extraKeys := make([]string, 0, len(config.Impersonate.Extra))
+	for key := range config.Impersonate.Extra {
+		extraKeys = append(extraKeys, key)
+	}
+	sort.Strings(extraKeys)
+
+	parts := []string{
+		config.Host, config.APIPath, config.UserAgent, config.ContentType,
+		config.BearerToken, config.BearerTokenFile, config.Username, config.Password,
+		config.CertFile, string(config.CertData), config.KeyFile, string(config.KeyData),
+		config.CAFile, string(config.CAData), config.ServerName, fmt.Sprint(config.Insecure),
+		config.Impersonate.UserName, fmt.Sprint(config.Impersonate.Groups),
+		fmt.Sprint(config.QPS), fmt.Sprint(config.Burst), fmt.Sprint(config.Timeout),
+	}
+	for _, key := range extraKeys {
+		parts = append(parts, key, fmt.Sprint(config.Impersonate.Extra[key]))
+	}
+
+	hash := sha256.New()
+	for _, part := range parts {
+		// Length prefix keeps parts from running into each other.
+		fmt.Fprintf(hash, "%d:%s", len(part), part)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func newClientCache(size int, ttl time.Duration) *clientCache {
+	return &clientCache{
+		entries: map[string]*list.Element{},
+		lru:     list.New(),
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+	}
+}
@@ -0,0 +1,70 @@
+package kubernetes
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	kubernetesapi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
+)
+
+func TestClientCache(t *testing.T) {
+	now := time.Now()
+	cache := newClientCache(2, time.Minute)
+	cache.now = func() time.Time { return now }
+	config := func(token string) *rest.Config {
+		return &rest.Config{Host: "http://localhost:8080", BearerToken: token}
+	}
+
+	first, _ := cache.Kubernetes(config("first"))
+	if cached, _ := cache.Kubernetes(config("first")); cached != first {
+		t.Errorf("Kubernetes(): expected client of the same credentials to be reused")
+	}
+
+	if other, _ := cache.Kubernetes(config("second")); other == first {
+		t.Errorf("Kubernetes(): expected clients of different credentials to differ")
+	}
+
+	// API extensions client shares the entry, but is created on its first use.
+	cache.APIExtensions(config("first"))
+	cache.APIExtensions(config("first"))
+
+	// Third credentials evict the least recently used second ones.
+	cache.Kubernetes(config("third"))
+	second, _ := cache.Kubernetes(config("second"))
+
+	now = now.Add(time.Minute)
+	if expired, _ := cache.Kubernetes(config("second")); expired == second {
+		t.Errorf("Kubernetes(): expected expired client to be created again")
+	}
+
+	expected := kubernetesapi.ClientCacheStats{Hits: 2, Misses: 6, Evictions: 3, Size: 2}
+	if actual := cache.Stats(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Stats(): expected %+v but got %+v", expected, actual)
+	}
+}
+
+func TestClientCacheKey(t *testing.T) {
+	base := &rest.Config{Host: "http://localhost:8080", BearerToken: "token"}
+	impersonating := rest.CopyConfig(base)
+	impersonating.Impersonate = rest.ImpersonationConfig{UserName: "jane", Groups: []string{"developers"}}
+	otherCluster := rest.CopyConfig(base)
+	otherCluster.Host = "http://localhost:8081"
+	withExec := rest.CopyConfig(base)
+	withExec.ExecProvider = &clientcmdapi.ExecConfig{Command: "kubectl-login"}
+
+	if clientCacheKey(base) != clientCacheKey(rest.CopyConfig(base)) {
+		t.Errorf("clientCacheKey(): expected equal configs to have the same key")
+	}
+
+	if clientCacheKey(base) == clientCacheKey(impersonating) || clientCacheKey(base) == clientCacheKey(otherCluster) {
+		t.Errorf("clientCacheKey(): expected different identities and clusters to have different keys")
+	}
+
+	if len(clientCacheKey(withExec)) > 0 {
+		t.Errorf("clientCacheKey(): expected configs with exec provider not to be cached")
+	}
+}
@@ -0,0 +1,176 @@
+package kubernetes
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/emicklei/go-restful/v3"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+	kubernetesapi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
+)
+
+// cluster holds the backend's own config and clients of a single cluster served by the portal.
+type cluster struct {
+	config        *rest.Config
+	kubernetes    kubernetes.Interface
+	apiExtensions apiextensionsclientset.Interface
+	dynamic       dynamic.Interface
+	discovery     *discoveryCache
+}
+
+// Clusters returns names of all clusters served by the portal. The default cluster is always the first one.
+func (self *kubernetesManager) Clusters() []string {
+	result := make([]string, 0, len(self.clusters))
+	for name := range self.clusters {
+		if name != kubernetesapi.DefaultClusterName {
+			result = append(result, name)
+		}
+	}
+
+	sort.Strings(result)
+	return append([]string{kubernetesapi.DefaultClusterName}, result...)
+}
+
+func (self *kubernetesManager) InsecureClusterKubernetes(name string) (kubernetes.Interface, error) {
+	cluster, err := self.clusterByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return cluster.kubernetes, nil
+}
+
+func (self *kubernetesManager) APIResources(req *restful.Request) (*kubernetesapi.APIResourceList, error) {
+	cluster, err := self.cluster(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return cluster.discovery.Resources()
+}
+
+func (self *kubernetesManager) APIResource(req *restful.Request, group, version,
+	resource string) (*kubernetesapi.APIResource, error) {
+	cluster, err := self.cluster(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return cluster.discovery.Resource(group, version, resource)
+}
+
+// cluster returns the cluster selected by the cluster path parameter of the request. Routes without the
+// parameter work against the default cluster.
+func (self *kubernetesManager) cluster(req *restful.Request) (*cluster, error) {
+	return self.clusterByName(req.PathParameter(kubernetesapi.ClusterPathParameter))
+}
+
+func (self *kubernetesManager) clusterByName(name string) (*cluster, error) {
+	if len(name) == 0 {
+		name = kubernetesapi.DefaultClusterName
+	}
+
+	cluster, exists := self.clusters[name]
+	if !exists {
+		return nil, errors.NewNotFound(fmt.Sprintf("cluster %s not found", name))
+	}
+
+	return cluster, nil
+}
+
+func (self *kubernetesManager) initClusters() {
+	self.clusters = map[string]*cluster{
+		kubernetesapi.DefaultClusterName: {
+			config:        self.insecureConfig,
+			kubernetes:    self.insecureKubernetes,
+			apiExtensions: self.insecureAPIExtensionsKubernetes,
+			dynamic:       self.insecureDynamicKubernetes,
+			discovery:     newDiscoveryCache(self.insecureKubernetes.Discovery()),
+		},
+	}
+
+	for _, context := range self.kubeConfigContexts {
+		config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			&clientcmd.ClientConfigLoadingRules{ExplicitPath: self.kubeConfigPath},
+			&clientcmd.ConfigOverrides{CurrentContext: context}).ClientConfig()
+		self.addCluster(context, config, err)
+	}
+
+	if len(self.kubeConfigDir) == 0 {
+		return
+	}
+
+	files, err := ioutil.ReadDir(self.kubeConfigDir)
+	if err != nil {
+		log.Printf("Could not read kubeconfig directory %s. Reason: %s", self.kubeConfigDir, err)
+		return
+	}
+
+	// Every file uses its current context. Cluster is named after the file, without the extension.
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+
+		name := strings.TrimSuffix(file.Name(), filepath.Ext(file.Name()))
+		config, err := clientcmd.BuildConfigFromFlags("", filepath.Join(self.kubeConfigDir, file.Name()))
+		self.addCluster(name, config, err)
+	}
+}
+
+// addCluster creates clients of the cluster. Clusters that can not be configured are skipped, so that a single
+// broken kubeconfig does not take down the whole portal.
+func (self *kubernetesManager) addCluster(name string, config *rest.Config, err error) {
+	if err != nil {
+		log.Printf("Skipping cluster %s. Reason: %s", name, err)
+		return
+	}
+
+	if len(name) == 0 || strings.Contains(name, "/") {
+		log.Printf("Skipping cluster %q, name can not be empty or contain /", name)
+		return
+	}
+
+	if _, exists := self.clusters[name]; exists {
+		log.Printf("Skipping cluster %s, cluster with the same name already exists", name)
+		return
+	}
+
+	self.initConfig(config)
+	k8sClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		log.Printf("Skipping cluster %s. Reason: %s", name, err)
+		return
+	}
+
+	apiExtensionsClient, err := apiextensionsclientset.NewForConfig(config)
+	if err != nil {
+		log.Printf("Skipping cluster %s. Reason: %s", name, err)
+		return
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		log.Printf("Skipping cluster %s. Reason: %s", name, err)
+		return
+	}
+
+	log.Printf("Using cluster %s: %s", name, config.Host)
+	self.clusters[name] = &cluster{
+		config:        config,
+		kubernetes:    k8sClient,
+		apiExtensions: apiExtensionsClient,
+		dynamic:       dynamicClient,
+		discovery:     newDiscoveryCache(k8sClient.Discovery()),
+	}
+}
@@ -0,0 +1,201 @@
+package kubernetes_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/emicklei/go-restful/v3"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/args"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/auth/jwe"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes"
+	kubernetesapi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
+)
+
+const testKubeConfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: test-cluster
+  cluster:
+    server: %s
+users:
+- name: test-user
+  user:
+    token: test-token
+contexts:
+- name: test-context
+  context:
+    cluster: test-cluster
+    user: test-user
+current-context: test-context
+`
+
+func TestClusters(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"staging.yaml": "http://staging:8080",
+		"dev.conf":     "http://dev:8080",
+		"broken":       "",
+		".hidden":      "http://hidden:8080",
+	}
+	for name, server := range files {
+		content := "invalid: ["
+		if len(server) > 0 {
+			content = fmt.Sprintf(testKubeConfig, server)
+		}
+
+		if err = ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manager := kubernetes.NewMultiClusterKubernetesManager("", "http://localhost:8080", nil, dir)
+	expected := []string{kubernetesapi.DefaultClusterName, "dev", "staging"}
+	if actual := manager.Clusters(); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Clusters(): Expected %v but got %v", expected, actual)
+	}
+
+	cases := []struct {
+		cluster      string
+		expectedHost string
+		expectedErr  bool
+	}{
+		{"", "http://localhost:8080", false},
+		{"dev", "http://dev:8080", false},
+		{"staging", "http://staging:8080", false},
+		{"unknown", "", true},
+	}
+
+	args.GetHolderBuilder().SetEnableSkipLogin(true)
+	for _, c := range cases {
+		req := restful.NewRequest(&http.Request{Header: http.Header{}})
+		req.PathParameters()[kubernetesapi.ClusterPathParameter] = c.cluster
+
+		config, err := manager.Config(req)
+		if (err != nil) != c.expectedErr {
+			t.Fatalf("Config(%s): Expected error to be %v but got: %v", c.cluster, c.expectedErr, err)
+		}
+
+		if err == nil && config.Host != c.expectedHost {
+			t.Errorf("Config(%s): Expected host %s but got %s", c.cluster, c.expectedHost, config.Host)
+		}
+
+		if _, err = manager.Kubernetes(req); (err != nil) != c.expectedErr {
+			t.Errorf("Kubernetes(%s): Expected error to be %v but got: %v", c.cluster, c.expectedErr, err)
+		}
+	}
+}
+
+func TestHasAccess(t *testing.T) {
+	defaultServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer defaultServer.Close()
+	devServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"major": "1", "minor": "20"}`)
+	}))
+	defer devServer.Close()
+
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	content := fmt.Sprintf(testKubeConfig, devServer.URL)
+	if err = ioutil.WriteFile(filepath.Join(dir, "dev"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := kubernetes.NewMultiClusterKubernetesManager("", defaultServer.URL, nil, dir)
+	cases := []struct {
+		cluster     string
+		expectedErr bool
+	}{
+		{"", true},
+		{kubernetesapi.DefaultClusterName, true},
+		{"dev", false},
+		{"unknown", true},
+	}
+
+	for _, c := range cases {
+		err := manager.HasAccess(api.AuthInfo{Token: "test-token"}, c.cluster)
+		if (err != nil) != c.expectedErr {
+			t.Errorf("HasAccess(%s): Expected error to be %v but got: %v", c.cluster, c.expectedErr, err)
+		}
+	}
+}
+
+func TestClusterBoundToken(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"dev", "staging"} {
+		content := fmt.Sprintf(testKubeConfig, "http://"+name+":8080")
+		if err = ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manager := kubernetes.NewMultiClusterKubernetesManager("", "http://localhost:8080", nil, dir)
+	tokenManager := jwe.NewJWETokenManager(jwe.NewRSAKeyHolder(fake.NewSimpleClientset(), "kube-portal"))
+	manager.SetTokenManager(tokenManager)
+
+	devToken, _ := tokenManager.Generate(api.AuthInfo{Impersonate: "jane"}, "jane", "dev")
+	defaultToken, _ := tokenManager.Generate(api.AuthInfo{Token: "test-token"}, "jane", "")
+	cases := []struct {
+		token        string
+		cluster      string
+		expectedHost string
+	}{
+		{devToken, "dev", "http://dev:8080"},
+		{devToken, "staging", ""},
+		{devToken, "", ""},
+		{devToken, kubernetesapi.DefaultClusterName, ""},
+		{defaultToken, "", "http://localhost:8080"},
+		{defaultToken, kubernetesapi.DefaultClusterName, "http://localhost:8080"},
+		{defaultToken, "dev", ""},
+	}
+
+	args.GetHolderBuilder().SetEnableSkipLogin(false)
+	for _, c := range cases {
+		req := restful.NewRequest(&http.Request{Header: http.Header{}})
+		req.Request.Header.Set(kubernetes.JWETokenHeader, c.token)
+		req.PathParameters()[kubernetesapi.ClusterPathParameter] = c.cluster
+
+		config, err := manager.Config(req)
+		if len(c.expectedHost) == 0 {
+			if !errors.IsUnauthorized(err) {
+				t.Errorf("Config(%s): Expected token of other cluster to be unauthorized but got: %v", c.cluster, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("Config(%s): Expected no error but got: %s", c.cluster, err)
+		}
+
+		if config.Host != c.expectedHost {
+			t.Errorf("Config(%s): Expected host %s but got %s", c.cluster, c.expectedHost, config.Host)
+		}
+	}
+}
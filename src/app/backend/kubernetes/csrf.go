@@ -0,0 +1,89 @@
+package kubernetes
+
+import (
+	"context"
+	"log"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+	kubernetesapi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
+)
+
+// csrfTokenManager keeps the key used to sign CSRF tokens in the CsrfTokenSecretName secret, so that tokens
+// generated by one replica are accepted by the others.
+type csrfTokenManager struct {
+	token      string
+	kubernetes kubernetes.Interface
+	namespace  string
+}
+
+func (self *csrfTokenManager) Token() string {
+	return self.token
+}
+
+func (self *csrfTokenManager) init() {
+	secret, err := self.kubernetes.CoreV1().Secrets(self.namespace).Get(context.TODO(),
+		kubernetesapi.CsrfTokenSecretName, metaV1.GetOptions{})
+	if errors.IsNotFound(err) {
+		self.create()
+		return
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
+	if token := string(secret.Data[kubernetesapi.CsrfTokenSecretData]); len(token) > 0 {
+		self.token = token
+		return
+	}
+
+	log.Printf("Storing new CSRF key in %s secret", kubernetesapi.CsrfTokenSecretName)
+	self.token = kubernetesapi.GenerateCSRFKey()
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+	secret.Data[kubernetesapi.CsrfTokenSecretData] = []byte(self.token)
+	if _, err = self.kubernetes.CoreV1().Secrets(self.namespace).Update(context.TODO(), secret,
+		metaV1.UpdateOptions{}); err != nil {
+		panic(err)
+	}
+}
+
+func (self *csrfTokenManager) create() {
+	token := kubernetesapi.GenerateCSRFKey()
+	secret := &v1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      kubernetesapi.CsrfTokenSecretName,
+			Namespace: self.namespace,
+		},
+		Data: map[string][]byte{kubernetesapi.CsrfTokenSecretData: []byte(token)},
+	}
+
+	_, err := self.kubernetes.CoreV1().Secrets(self.namespace).Create(context.TODO(), secret, metaV1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		// Other replica has been faster, use its key.
+		self.init()
+		return
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
+	log.Printf("Storing new CSRF key in %s secret", kubernetesapi.CsrfTokenSecretName)
+	self.token = token
+}
+
+func NewCsrfTokenManager(kubernetes kubernetes.Interface, namespace string) kubernetesapi.CsrfTokenManager {
+	manager := &csrfTokenManager{
+		kubernetes: kubernetes,
+		namespace:  namespace,
+	}
+
+	manager.init()
+	return manager
+}
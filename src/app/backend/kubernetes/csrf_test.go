@@ -0,0 +1,22 @@
+package kubernetes_test
+
+import (
+	"testing"
+
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes"
+)
+
+func TestCsrfTokenManager(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	first := kubernetes.NewCsrfTokenManager(fakeClient, "kube-portal")
+	if len(first.Token()) == 0 {
+		t.Fatal("Token(): expected token to be generated")
+	}
+
+	second := kubernetes.NewCsrfTokenManager(fakeClient, "kube-portal")
+	if first.Token() != second.Token() {
+		t.Error("Token(): expected replicas to share the CSRF key stored in the secret")
+	}
+}
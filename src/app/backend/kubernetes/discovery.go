@@ -0,0 +1,142 @@
+package kubernetes
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+	kubernetesapi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
+)
+
+const (
+	// Resources discovered on the cluster are refreshed at most after this interval.
+	discoveryRefreshInterval = 5 * time.Minute
+	// Minimal interval between two refreshes triggered by unknown resource, e.g. of a CRD installed in the
+	// meantime.
+	unknownResourceRefreshInterval = 10 * time.Second
+)
+
+// discoveryCache holds API resources served by a single cluster. Discovery is readable by every authenticated
+// user, so the cache is shared by all users of the cluster.
+type discoveryCache struct {
+	mux       sync.Mutex
+	client    discovery.DiscoveryInterface
+	resources *kubernetesapi.APIResourceList
+	now       func() time.Time
+}
+
+func (self *discoveryCache) Resources() (*kubernetesapi.APIResourceList, error) {
+	return self.get(discoveryRefreshInterval)
+}
+
+// Resource returns the resource of the group version. Discovery is refreshed when the resource is not known,
+// so that resources added to the cluster can be browsed without waiting for the regular refresh.
+func (self *discoveryCache) Resource(group, version, resource string) (*kubernetesapi.APIResource, error) {
+	for _, maxAge := range []time.Duration{discoveryRefreshInterval, unknownResourceRefreshInterval} {
+		list, err := self.get(maxAge)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range list.Resources {
+			if found := &list.Resources[i]; found.Group == group && found.Version == version &&
+				found.Resource == resource {
+				return found, nil
+			}
+		}
+	}
+
+	return nil, errors.NewNotFound(fmt.Sprintf("resource %s not found",
+		schema.GroupVersionResource{Group: group, Version: version, Resource: resource}))
+}
+
+// get returns cached resources, refreshing them when they are older than maxAge. Stale resources are returned
+// when the refresh fails.
+func (self *discoveryCache) get(maxAge time.Duration) (*kubernetesapi.APIResourceList, error) {
+	self.mux.Lock()
+	defer self.mux.Unlock()
+
+	if self.resources != nil && self.now().Sub(self.resources.RefreshedAt) < maxAge {
+		return self.resources, nil
+	}
+
+	resources, err := self.refresh()
+	if err != nil {
+		if self.resources == nil {
+			return nil, err
+		}
+
+		log.Printf("Could not refresh API resources, using the cached ones. Reason: %s", err)
+		return self.resources, nil
+	}
+
+	self.resources = resources
+	return resources, nil
+}
+
+func (self *discoveryCache) refresh() (*kubernetesapi.APIResourceList, error) {
+	_, lists, err := self.client.ServerGroupsAndResources()
+	nonCriticalErrors := make([]error, 0)
+	if err != nil {
+		// Groups that failed, e.g. of an unavailable aggregated API server, are skipped.
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			return nil, err
+		}
+
+		log.Printf("Could not discover some API groups. Reason: %s", err)
+		nonCriticalErrors = append(nonCriticalErrors, err)
+	}
+
+	result := &kubernetesapi.APIResourceList{
+		Resources:   make([]kubernetesapi.APIResource, 0),
+		RefreshedAt: self.now(),
+		Errors:      nonCriticalErrors,
+	}
+
+	for _, list := range lists {
+		groupVersion, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			log.Printf("Skipping invalid group version %s. Reason: %s", list.GroupVersion, err)
+			continue
+		}
+
+		for _, resource := range list.APIResources {
+			// Subresources, e.g. pods/log, can not be listed.
+			if strings.Contains(resource.Name, "/") {
+				continue
+			}
+
+			result.Resources = append(result.Resources, kubernetesapi.APIResource{
+				Group:      groupVersion.Group,
+				Version:    groupVersion.Version,
+				Resource:   resource.Name,
+				Kind:       resource.Kind,
+				Namespaced: resource.Namespaced,
+				Verbs:      resource.Verbs,
+			})
+		}
+	}
+
+	sort.Slice(result.Resources, func(i, j int) bool {
+		a, b := result.Resources[i], result.Resources[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		return a.Resource < b.Resource
+	})
+	return result, nil
+}
+
+func newDiscoveryCache(client discovery.DiscoveryInterface) *discoveryCache {
+	return &discoveryCache{client: client, now: time.Now}
+}
@@ -0,0 +1,64 @@
+package kubernetes
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+	kubernetesapi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
+)
+
+func TestDiscoveryCache(t *testing.T) {
+	discovery := fake.NewSimpleClientset().Discovery().(*fakediscovery.FakeDiscovery)
+	discovery.Resources = []*metaV1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metaV1.APIResource{
+				{Name: "pods", Kind: "Pod", Namespaced: true, Verbs: []string{"get", "list"}},
+				{Name: "pods/log", Kind: "Pod", Namespaced: true, Verbs: []string{"get"}},
+			},
+		},
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metaV1.APIResource{{Name: "deployments", Kind: "Deployment", Namespaced: true}},
+		},
+	}
+
+	now := time.Now()
+	cache := newDiscoveryCache(discovery)
+	cache.now = func() time.Time { return now }
+
+	list, err := cache.Resources()
+	if err != nil {
+		t.Fatalf("Resources(): expected no error but got: %s", err)
+	}
+
+	expected := []kubernetesapi.APIResource{
+		{Group: "", Version: "v1", Resource: "pods", Kind: "Pod", Namespaced: true, Verbs: []string{"get", "list"}},
+		{Group: "apps", Version: "v1", Resource: "deployments", Kind: "Deployment", Namespaced: true},
+	}
+	if !reflect.DeepEqual(list.Resources, expected) {
+		t.Errorf("Resources(): expected %+v but got %+v", expected, list.Resources)
+	}
+
+	// CRD installed after the last refresh.
+	discovery.Resources = append(discovery.Resources, &metaV1.APIResourceList{
+		GroupVersion: "example.com/v1alpha1",
+		APIResources: []metaV1.APIResource{{Name: "widgets", Kind: "Widget"}},
+	})
+
+	if _, err = cache.Resource("example.com", "v1alpha1", "widgets"); !errors.IsNotFound(err) {
+		t.Errorf("Resource(): expected resource to be unknown right after the refresh but got %v", err)
+	}
+
+	now = now.Add(unknownResourceRefreshInterval)
+	resource, err := cache.Resource("example.com", "v1alpha1", "widgets")
+	if err != nil || resource.Kind != "Widget" || resource.Namespaced {
+		t.Errorf("Resource(): expected cluster scoped Widget resource but got %+v, %v", resource, err)
+	}
+}
@@ -1,13 +1,25 @@
 package kubernetes
 
 import (
+	"context"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/emicklei/go-restful/v3"
+	authorizationapi "k8s.io/api/authorization/v1"
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 
+	"github.com/donghoon-khan/kubeportal/src/app/backend/args"
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
 	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
 	kubernetesapi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
 )
@@ -19,20 +31,35 @@ const (
 	DefaultContentType         = "application/vnd.kubernetes.protobuf"
 	DefaultCmdConfigName       = "kubernetes"
 	JWETokenHeader             = "jweToken"
+	AuthorizationHeader        = "Authorization"
+	AuthorizationTokenPrefix   = "Bearer "
 	DefaultUserAgent           = "kube-portal"
+	ImpersonateUserHeader      = "Impersonate-User"
+	ImpersonateGroupHeader     = "Impersonate-Group"
 	ImpersonateUserExtraHeader = "Impersonate-Extra-"
+	// URLTokenParameter is the query parameter carrying the token returned by URLToken.
+	URLTokenParameter = "token"
+	// Clients open the URL right after the token is issued, streams stay open after it expires.
+	URLTokenTTL = 30 * time.Second
 )
 
 // Version of this binary
 var Version = "UNKNOWN"
 
 type kubernetesManager struct {
-	csrfKey         string
-	kubeConfigPath  string
-	apiserverHost   string
-	inClusterConfig *rest.Config
-	//tokenManager authApi.TokenManager
+	csrfKey                         string
+	csrfKeyOnce                     sync.Once
+	kubeConfigPath                  string
+	apiserverHost                   string
+	kubeConfigContexts              []string
+	kubeConfigDir                   string
+	clusters                        map[string]*cluster
+	inClusterConfig                 *rest.Config
+	tokenManager                    authApi.TokenManager
+	personalAccessTokenManager      authApi.PersonalAccessTokenManager
+	clientCache                     *clientCache
 	insecureAPIExtensionsKubernetes apiextensionsclientset.Interface
+	insecureDynamicKubernetes       dynamic.Interface
 	//insecurePluginClient pluginclientset.Interface
 	insecureKubernetes kubernetes.Interface
 	insecureConfig     *rest.Config
@@ -45,7 +72,13 @@ func (self *kubernetesManager) Kubernetes(req *restful.Request) (kubernetes.Inte
 	if self.isSecureModeEnabled(req) {
 		return self.secureKubernetes(req)
 	}
-	return self.InsecureKubernetes(), nil
+
+	cluster, err := self.cluster(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return cluster.kubernetes, nil
 }
 
 func (self *kubernetesManager) APIExtensionsKubernetes(req *restful.Request) (apiextensionsclientset.Interface, error) {
@@ -57,7 +90,56 @@ func (self *kubernetesManager) APIExtensionsKubernetes(req *restful.Request) (ap
 		return self.secureAPIExtensionsKubernetes(req)
 	}
 
-	return self.InsecureAPIExtensionsKubernetes(), nil
+	cluster, err := self.cluster(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return cluster.apiExtensions, nil
+}
+
+func (self *kubernetesManager) DynamicKubernetes(req *restful.Request) (dynamic.Interface, error) {
+	if req == nil {
+		return nil, errors.NewBadRequest("request can not be nil")
+	}
+
+	if self.isSecureModeEnabled(req) {
+		return self.secureDynamicKubernetes(req)
+	}
+
+	cluster, err := self.cluster(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return cluster.dynamic, nil
+}
+
+// VerberClient returns ResourceVerber that works with credentials of the request against the cluster selected by
+// the request.
+func (self *kubernetesManager) VerberClient(req *restful.Request) (kubernetesapi.ResourceVerber, error) {
+	k8sClient, err := self.Kubernetes(req)
+	if err != nil {
+		return nil, err
+	}
+
+	apiExtensionsClient, err := self.APIExtensionsKubernetes(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewResourceVerber(
+		k8sClient.CoreV1().RESTClient(),
+		k8sClient.ExtensionsV1beta1().RESTClient(),
+		k8sClient.AppsV1().RESTClient(),
+		k8sClient.BatchV1().RESTClient(),
+		k8sClient.BatchV1beta1().RESTClient(),
+		k8sClient.AutoscalingV1().RESTClient(),
+		k8sClient.StorageV1().RESTClient(),
+		k8sClient.RbacV1().RESTClient(),
+		apiExtensionsClient.ApiextensionsV1().RESTClient(),
+		k8sClient.NetworkingV1().RESTClient(),
+	), nil
 }
 
 func (self *kubernetesManager) InsecureKubernetes() kubernetes.Interface {
@@ -68,19 +150,330 @@ func (self *kubernetesManager) InsecureAPIExtensionsKubernetes() apiextensionscl
 	return self.insecureAPIExtensionsKubernetes
 }
 
-// TODO: Implement check secure mode
+// CanI returns true when the user bound to the request is allowed to perform the action described by the
+// access review. Any error is logged and treated as a denial.
+func (self *kubernetesManager) CanI(req *restful.Request, ssar *authorizationapi.SelfSubjectAccessReview) bool {
+	k8sClient, err := self.Kubernetes(req)
+	if err != nil {
+		log.Println(err)
+		return false
+	}
+
+	response, err := k8sClient.AuthorizationV1().SelfSubjectAccessReviews().Create(context.TODO(), ssar,
+		metaV1.CreateOptions{})
+	if err != nil {
+		log.Println(err)
+		return false
+	}
+
+	return response.Status.Allowed
+}
+
+func (self *kubernetesManager) Config(req *restful.Request) (*rest.Config, error) {
+	if !self.isSecureModeEnabled(req) {
+		cluster, err := self.cluster(req)
+		if err != nil {
+			return nil, err
+		}
+
+		return rest.CopyConfig(cluster.config), nil
+	}
+
+	cmdConfig, err := self.ClientCmdConfig(req)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := cmdConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	impersonationConfig, err := self.extractImpersonationConfig(req)
+	if err != nil {
+		return nil, err
+	}
+
+	self.initConfig(config)
+	if len(impersonationConfig.UserName) > 0 {
+		if len(config.Impersonate.UserName) > 0 {
+			return nil, errors.NewBadRequest("impersonated user can not impersonate other users")
+		}
+
+		config.Impersonate = impersonationConfig
+	}
+
+	return config, nil
+}
+
+func (self *kubernetesManager) ClientCmdConfig(req *restful.Request) (clientcmd.ClientConfig, error) {
+	authInfo, err := self.extractAuthInfo(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if authInfo == nil {
+		return nil, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	cluster, err := self.cluster(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.buildCmdConfig(self.resolveAuthInfo(authInfo, cluster.config), cluster.config), nil
+}
+
+// CSRFKey returns the key used to sign CSRF tokens. It is loaded from, or stored in, the CsrfTokenSecretName
+// secret on the first call.
+func (self *kubernetesManager) CSRFKey() string {
+	self.csrfKeyOnce.Do(func() {
+		self.csrfKey = NewCsrfTokenManager(self.insecureKubernetes, args.Holder.GetNamespace()).Token()
+	})
+
+	return self.csrfKey
+}
+
+func (self *kubernetesManager) SetTokenManager(manager authApi.TokenManager) {
+	self.tokenManager = manager
+}
+
+func (self *kubernetesManager) SetPersonalAccessTokenManager(manager authApi.PersonalAccessTokenManager) {
+	self.personalAccessTokenManager = manager
+}
+
+func (self *kubernetesManager) ClientCacheStats() kubernetesapi.ClientCacheStats {
+	return self.clientCache.Stats()
+}
+
+func (self *kubernetesManager) AuthInfo(req *restful.Request) (*api.AuthInfo, error) {
+	return self.extractAuthInfo(req)
+}
+
+func (self *kubernetesManager) URLToken(req *restful.Request, path string) (string, error) {
+	authInfo, err := self.extractAuthInfo(req)
+	if err != nil {
+		return "", err
+	}
+
+	if authInfo == nil || self.tokenManager == nil {
+		return "", errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	return self.tokenManager.GenerateURLToken(*authInfo, path, URLTokenTTL)
+}
+
+// isSecureModeEnabled returns true when request should be made with the credentials provided by the user.
+// Backend's own credentials are used only when login is skippable and user did not provide any.
 func (self *kubernetesManager) isSecureModeEnabled(req *restful.Request) bool {
-	return false
+	if !args.Holder.GetEnableSkipLogin() {
+		return true
+	}
+
+	// Impersonation has to be authorized with user's credentials, never with the backend's ones.
+	return len(self.extractTokenFromHeader(req.HeaderParameter(AuthorizationHeader))) > 0 ||
+		len(req.HeaderParameter(JWETokenHeader)) > 0 ||
+		len(self.extractURLToken(req)) > 0 ||
+		self.hasImpersonationHeaders(req)
 }
 
-// TODO: Implement secure kubernetes client
 func (self *kubernetesManager) secureKubernetes(req *restful.Request) (kubernetes.Interface, error) {
-	return self.InsecureKubernetes(), nil
+	config, err := self.Config(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.clientCache.Kubernetes(config)
 }
 
-// TODO: Implemenet secure kubernetes extensions client
 func (self *kubernetesManager) secureAPIExtensionsKubernetes(req *restful.Request) (apiextensionsclientset.Interface, error) {
-	return self.InsecureAPIExtensionsKubernetes(), nil
+	config, err := self.Config(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.clientCache.APIExtensions(config)
+}
+
+func (self *kubernetesManager) secureDynamicKubernetes(req *restful.Request) (dynamic.Interface, error) {
+	config, err := self.Config(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.clientCache.Dynamic(config)
+}
+
+// extractAuthInfo returns auth info based on the request headers. Authorization header takes precedence over
+// the JWE token issued by the portal, which takes precedence over the URL token in the query. Nil is returned when
+// request does not carry any credentials.
+func (self *kubernetesManager) extractAuthInfo(req *restful.Request) (*api.AuthInfo, error) {
+	token := self.extractTokenFromHeader(req.HeaderParameter(AuthorizationHeader))
+	if strings.HasPrefix(token, authApi.PersonalAccessTokenPrefix) {
+		if self.personalAccessTokenManager == nil {
+			return nil, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+		}
+
+		return self.personalAccessTokenManager.Resolve(token, req.Request.Method)
+	}
+
+	if len(token) > 0 {
+		return &api.AuthInfo{Token: token}, nil
+	}
+
+	jweToken := req.HeaderParameter(JWETokenHeader)
+	urlToken := self.extractURLToken(req)
+	if len(jweToken) == 0 && len(urlToken) == 0 {
+		return nil, nil
+	}
+
+	if self.tokenManager == nil {
+		return nil, errors.NewUnauthorized(errors.MsgLoginUnauthorizedError)
+	}
+
+	if len(jweToken) == 0 {
+		// URL token is bound to the path, which already selects the cluster.
+		return self.tokenManager.DecryptURLToken(urlToken, req.Request.URL.Path)
+	}
+
+	// Token is accepted only by the cluster the user logged in to.
+	return self.tokenManager.Decrypt(jweToken,
+		kubernetesapi.TokenCluster(req.PathParameter(kubernetesapi.ClusterPathParameter)))
+}
+
+// extractImpersonationConfig returns impersonation config based on the Impersonate-* request headers. The
+// API server still checks whether the user is allowed to impersonate given user, groups and extras.
+func (self *kubernetesManager) extractImpersonationConfig(req *restful.Request) (rest.ImpersonationConfig, error) {
+	result := rest.ImpersonationConfig{
+		UserName: req.HeaderParameter(ImpersonateUserHeader),
+		Groups:   req.Request.Header[ImpersonateGroupHeader],
+	}
+
+	for headerName, headerValues := range req.Request.Header {
+		if !strings.HasPrefix(headerName, ImpersonateUserExtraHeader) {
+			continue
+		}
+
+		extraName, err := url.PathUnescape(strings.ToLower(headerName[len(ImpersonateUserExtraHeader):]))
+		if err != nil {
+			return rest.ImpersonationConfig{}, errors.NewBadRequest(err.Error())
+		}
+
+		if result.Extra == nil {
+			result.Extra = make(map[string][]string)
+		}
+		result.Extra[extraName] = headerValues
+	}
+
+	if len(result.UserName) == 0 && (len(result.Groups) > 0 || len(result.Extra) > 0) {
+		return rest.ImpersonationConfig{}, errors.NewBadRequest(
+			ImpersonateUserHeader + " header is required to impersonate groups or extras")
+	}
+
+	return result, nil
+}
+
+func (self *kubernetesManager) hasImpersonationHeaders(req *restful.Request) bool {
+	for headerName := range req.Request.Header {
+		if headerName == ImpersonateUserHeader || headerName == ImpersonateGroupHeader ||
+			strings.HasPrefix(headerName, ImpersonateUserExtraHeader) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// extractURLToken returns token from the query of the URL only. Form values are not read, so that the request
+// body is left untouched.
+func (self *kubernetesManager) extractURLToken(req *restful.Request) string {
+	if req.Request.URL == nil {
+		return ""
+	}
+
+	return req.Request.URL.Query().Get(URLTokenParameter)
+}
+
+func (self *kubernetesManager) extractTokenFromHeader(authHeader string) string {
+	if len(authHeader) > len(AuthorizationTokenPrefix) &&
+		strings.EqualFold(authHeader[:len(AuthorizationTokenPrefix)], AuthorizationTokenPrefix) {
+		return strings.TrimSpace(authHeader[len(AuthorizationTokenPrefix):])
+	}
+
+	return ""
+}
+
+func (self *kubernetesManager) HasAccess(authInfo api.AuthInfo, clusterName string) error {
+	cluster, err := self.clusterByName(clusterName)
+	if err != nil {
+		return err
+	}
+
+	config, err := self.buildCmdConfig(self.resolveAuthInfo(&authInfo, cluster.config), cluster.config).ClientConfig()
+	if err != nil {
+		return err
+	}
+
+	k8sClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	_, err = k8sClient.Discovery().ServerVersion()
+	return err
+}
+
+// resolveAuthInfo completes auth info that carries only an identity to impersonate, e.g. the one issued by
+// the OIDC authenticator, with the backend's own credentials for the cluster. Such auth info can only come from
+// a token encrypted by the portal or a personal access token, because other credentials extracted from headers
+// always contain a token.
+func (self *kubernetesManager) resolveAuthInfo(authInfo *api.AuthInfo, config *rest.Config) *api.AuthInfo {
+	if len(authInfo.Impersonate) == 0 || self.hasCredentials(authInfo) {
+		return authInfo
+	}
+
+	result := self.buildAuthInfoFromConfig(config)
+	result.Impersonate = authInfo.Impersonate
+	result.ImpersonateGroups = authInfo.ImpersonateGroups
+	result.ImpersonateUserExtra = authInfo.ImpersonateUserExtra
+	return &result
+}
+
+func (self *kubernetesManager) hasCredentials(authInfo *api.AuthInfo) bool {
+	return len(authInfo.Token) > 0 || len(authInfo.Username) > 0 || len(authInfo.ClientCertificateData) > 0
+}
+
+func (self *kubernetesManager) buildAuthInfoFromConfig(config *rest.Config) api.AuthInfo {
+	return api.AuthInfo{
+		Token:                 config.BearerToken,
+		TokenFile:             config.BearerTokenFile,
+		ClientCertificate:     config.TLSClientConfig.CertFile,
+		ClientCertificateData: config.TLSClientConfig.CertData,
+		ClientKey:             config.TLSClientConfig.KeyFile,
+		ClientKeyData:         config.TLSClientConfig.KeyData,
+		Username:              config.Username,
+		Password:              config.Password,
+		AuthProvider:          config.AuthProvider,
+		Exec:                  config.ExecProvider,
+	}
+}
+
+func (self *kubernetesManager) buildCmdConfig(authInfo *api.AuthInfo, config *rest.Config) clientcmd.ClientConfig {
+	cmdConfig := api.NewConfig()
+	cmdConfig.Clusters[DefaultCmdConfigName] = &api.Cluster{
+		Server:                   config.Host,
+		CertificateAuthority:     config.TLSClientConfig.CAFile,
+		CertificateAuthorityData: config.TLSClientConfig.CAData,
+		InsecureSkipTLSVerify:    config.TLSClientConfig.Insecure,
+	}
+	cmdConfig.AuthInfos[DefaultCmdConfigName] = authInfo
+	cmdConfig.Contexts[DefaultCmdConfigName] = &api.Context{
+		Cluster:  DefaultCmdConfigName,
+		AuthInfo: DefaultCmdConfigName,
+	}
+	cmdConfig.CurrentContext = DefaultCmdConfigName
+
+	return clientcmd.NewDefaultClientConfig(*cmdConfig, &clientcmd.ConfigOverrides{})
 }
 
 func (self *kubernetesManager) buildConfigFromFlags(apiserverHost, kubeConfigPath string) (*rest.Config, error) {
@@ -94,11 +487,14 @@ func (self *kubernetesManager) buildConfigFromFlags(apiserverHost, kubeConfigPat
 		return self.inClusterConfig, nil
 	}
 
-	return nil, errors.NewInvalid("could not create client config")
+	return nil, errors.NewInvalid("could not create client config, apiserver host or kubeconfig path is " +
+		"required when running outside of the cluster")
 }
 
 func (self *kubernetesManager) init() {
+	self.initInClusterConfig()
 	self.initInsecureKubernetes()
+	self.initClusters()
 }
 
 func (self *kubernetesManager) initConfig(config *rest.Config) {
@@ -120,10 +516,16 @@ func (self *kubernetesManager) initInsecureKubernetes() {
 		panic(err)
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(self.insecureConfig)
+	if err != nil {
+		panic(err)
+	}
+
 	//TODO: pluginClient 추가
 
 	self.insecureKubernetes = k8sClient
 	self.insecureAPIExtensionsKubernetes = apiextensionsclient
+	self.insecureDynamicKubernetes = dynamicClient
 	//TODO: pluginClient mapping
 }
 
@@ -137,15 +539,43 @@ func (self *kubernetesManager) initInsecureConfig() {
 	self.insecureConfig = config
 }
 
-// TODO: check is in cluster
+// initInClusterConfig loads service account config of the pod the portal runs in. It is used only when neither
+// apiserver host nor kubeconfig path is given.
+func (self *kubernetesManager) initInClusterConfig() {
+	if len(self.apiserverHost) > 0 || len(self.kubeConfigPath) > 0 {
+		log.Print("Skipping in-cluster config")
+		return
+	}
+
+	log.Print("Using in-cluster config to connect to apiserver")
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		log.Printf("Could not init in-cluster config. Reason: %s", err)
+		return
+	}
+
+	self.inClusterConfig = config
+}
+
 func (self *kubernetesManager) isRunningInCluster() bool {
-	return false
+	return self.inClusterConfig != nil
 }
 
 func NewKubernetesManager(kubeConfigPath, apiserverHost string) kubernetesapi.KubernetesManager {
+	return NewMultiClusterKubernetesManager(kubeConfigPath, apiserverHost, nil, "")
+}
+
+// NewMultiClusterKubernetesManager creates KubernetesManager that, next to the default cluster described by
+// kubeConfigPath and apiserverHost, serves clusters of the given kubeconfig contexts and of every kubeconfig
+// file in kubeConfigDir.
+func NewMultiClusterKubernetesManager(kubeConfigPath, apiserverHost string, kubeConfigContexts []string,
+	kubeConfigDir string) kubernetesapi.KubernetesManager {
 	result := &kubernetesManager{
-		kubeConfigPath: kubeConfigPath,
-		apiserverHost:  apiserverHost,
+		kubeConfigPath:     kubeConfigPath,
+		apiserverHost:      apiserverHost,
+		kubeConfigContexts: kubeConfigContexts,
+		kubeConfigDir:      kubeConfigDir,
+		clientCache:        newClientCache(clientCacheSize, clientCacheTTL),
 	}
 
 	result.init()
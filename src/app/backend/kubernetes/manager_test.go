@@ -1,11 +1,22 @@
 package kubernetes_test
 
 import (
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"testing"
 
+	"github.com/donghoon-khan/kubeportal/src/app/backend/args"
+	authApi "github.com/donghoon-khan/kubeportal/src/app/backend/auth/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/auth/pat"
 	"github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes"
+	kubernetesapi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
 	"github.com/emicklei/go-restful/v3"
+	authorizationapi "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
 )
 
 func TestNewKubernetesManager(t *testing.T) {
@@ -28,7 +39,9 @@ func TestNewKubernetesManager(t *testing.T) {
 
 func TestKubernetes(t *testing.T) {
 	cases := []struct {
-		request *restful.Request
+		request         *restful.Request
+		enableSkipLogin bool
+		expectedErr     bool
 	}{
 		{
 			&restful.Request{
@@ -36,16 +49,171 @@ func TestKubernetes(t *testing.T) {
 					Header: http.Header(map[string][]string{}),
 				},
 			},
+			true,
+			false,
+		},
+		{
+			&restful.Request{
+				Request: &http.Request{
+					Header: http.Header(map[string][]string{}),
+				},
+			},
+			false,
+			true,
+		},
+		{
+			&restful.Request{
+				Request: &http.Request{
+					Header: http.Header(map[string][]string{
+						"Authorization": {"Bearer test-token"},
+					}),
+				},
+			},
+			false,
+			false,
+		},
+		{
+			&restful.Request{
+				Request: &http.Request{
+					Header: http.Header(map[string][]string{
+						"Jwetoken": {"test-token"},
+					}),
+				},
+			},
+			true,
+			true,
 		},
 	}
 
 	for _, c := range cases {
+		args.GetHolderBuilder().SetEnableSkipLogin(c.enableSkipLogin)
 		k8sManager := kubernetes.NewKubernetesManager("", "http://localhost:8080")
 		_, err := k8sManager.Kubernetes(c.request)
 
-		if err != nil {
-			t.Fatalf("Client(%v): Expected client to be created but error was thrown:"+
-				" %s", c.request, err.Error())
+		if (err != nil) != c.expectedErr {
+			t.Fatalf("Client(%v): Expected error to be %v but got: %v", c.request, c.expectedErr, err)
+		}
+	}
+}
+
+func TestConfigImpersonation(t *testing.T) {
+	cases := []struct {
+		header      http.Header
+		expected    rest.ImpersonationConfig
+		expectedErr bool
+	}{
+		{
+			http.Header{"Authorization": {"Bearer test-token"}},
+			rest.ImpersonationConfig{},
+			false,
+		},
+		{
+			http.Header{
+				"Authorization":        {"Bearer test-token"},
+				"Impersonate-User":     {"jane"},
+				"Impersonate-Group":    {"developers", "operators"},
+				"Impersonate-Extra-Id": {"1234"},
+			},
+			rest.ImpersonationConfig{
+				UserName: "jane",
+				Groups:   []string{"developers", "operators"},
+				Extra:    map[string][]string{"id": {"1234"}},
+			},
+			false,
+		},
+		{
+			http.Header{
+				"Authorization":     {"Bearer test-token"},
+				"Impersonate-Group": {"developers"},
+			},
+			rest.ImpersonationConfig{},
+			true,
+		},
+		{
+			http.Header{"Impersonate-User": {"jane"}},
+			rest.ImpersonationConfig{},
+			true,
+		},
+	}
+
+	args.GetHolderBuilder().SetEnableSkipLogin(true)
+	k8sManager := kubernetes.NewKubernetesManager("", "http://localhost:8080")
+	for _, c := range cases {
+		config, err := k8sManager.Config(&restful.Request{Request: &http.Request{Header: c.header}})
+		if (err != nil) != c.expectedErr {
+			t.Fatalf("Config(%v): Expected error to be %v but got: %v", c.header, c.expectedErr, err)
+		}
+
+		if err == nil && !reflect.DeepEqual(config.Impersonate, c.expected) {
+			t.Errorf("Config(%v): Expected impersonation config %+v but got %+v", c.header, c.expected,
+				config.Impersonate)
+		}
+	}
+}
+
+func TestAuthInfoPersonalAccessToken(t *testing.T) {
+	patManager := pat.NewPersonalAccessTokenManager(fake.NewSimpleClientset(), "kube-portal")
+	token, _ := patManager.Create(authApi.Identity{Username: "jane", Groups: []string{"developers"}},
+		authApi.PersonalAccessTokenSpec{Name: "ci", Scopes: []authApi.PersonalAccessTokenScope{authApi.ReadScope}})
+
+	cases := []struct {
+		method, token string
+		expected      *api.AuthInfo
+	}{
+		{http.MethodGet, token.Token, &api.AuthInfo{Impersonate: "jane", ImpersonateGroups: []string{"developers"}}},
+		{http.MethodPost, token.Token, nil},
+		{http.MethodGet, authApi.PersonalAccessTokenPrefix + "unknown_token", nil},
+		{http.MethodGet, "test-token", &api.AuthInfo{Token: "test-token"}},
+	}
+
+	k8sManager := kubernetes.NewKubernetesManager("", "http://localhost:8080")
+	k8sManager.SetPersonalAccessTokenManager(patManager)
+	for _, c := range cases {
+		actual, err := k8sManager.AuthInfo(&restful.Request{Request: &http.Request{
+			Method: c.method,
+			Header: http.Header{"Authorization": {"Bearer " + c.token}},
+		}})
+		if c.expected == nil {
+			if err == nil {
+				t.Errorf("AuthInfo(%s %s): Expected error but got %+v", c.method, c.token, actual)
+			}
+			continue
+		}
+
+		if err != nil || !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("AuthInfo(%s %s): Expected %+v but got %+v, %v", c.method, c.token, c.expected, actual, err)
+		}
+	}
+}
+
+func TestCanI(t *testing.T) {
+	cases := []struct {
+		header   http.Header
+		allowed  bool
+		expected bool
+	}{
+		{http.Header{"Authorization": {"Bearer test-token"}}, true, true},
+		{http.Header{"Authorization": {"Bearer test-token"}}, false, false},
+		{http.Header{}, true, false},
+	}
+
+	for _, c := range cases {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			review := new(authorizationapi.SelfSubjectAccessReview)
+			_ = json.NewDecoder(r.Body).Decode(review)
+			review.Status.Allowed = c.allowed
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(review)
+		}))
+
+		args.GetHolderBuilder().SetEnableSkipLogin(false)
+		k8sManager := kubernetes.NewKubernetesManager("", server.URL)
+		ssar := kubernetesapi.ToSelfSubjectAccessReview("default", "", "pod", "list")
+		actual := k8sManager.CanI(&restful.Request{Request: &http.Request{Header: c.header}}, ssar)
+		server.Close()
+
+		if actual != c.expected {
+			t.Errorf("CanI(%v): Expected %v but got %v", c.header, c.expected, actual)
 		}
 	}
 }
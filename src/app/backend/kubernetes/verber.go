@@ -0,0 +1,166 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+	kubernetesapi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
+)
+
+// RESTClient is the part of rest.Interface used by the verber.
+type RESTClient interface {
+	Delete() *rest.Request
+	Put() *rest.Request
+	Get() *rest.Request
+}
+
+// resourceVerber can get, update and delete any resource kind listed in api.KindToAPIMapping.
+type resourceVerber struct {
+	client              RESTClient
+	extensionsClient    RESTClient
+	appsClient          RESTClient
+	batchClient         RESTClient
+	betaBatchClient     RESTClient
+	autoscalingClient   RESTClient
+	storageClient       RESTClient
+	rbacClient          RESTClient
+	apiExtensionsClient RESTClient
+	networkingClient    RESTClient
+}
+
+func (verber *resourceVerber) Delete(kind string, namespaceSet bool, namespace string, name string) error {
+	client, resourceSpec, err := verber.getResourceSpecFromKind(kind, namespaceSet)
+	if err != nil {
+		return err
+	}
+
+	// Cascade delete is what users typically expect.
+	propagationPolicy := metaV1.DeletePropagationForeground
+	req := client.Delete().
+		Resource(resourceSpec.Resource).
+		Name(name).
+		Body(&metaV1.DeleteOptions{PropagationPolicy: &propagationPolicy})
+	if resourceSpec.Namespaced {
+		req.Namespace(namespace)
+	}
+
+	return req.Do(context.TODO()).Error()
+}
+
+func (verber *resourceVerber) Put(kind string, namespaceSet bool, namespace string, name string,
+	object *runtime.Unknown) error {
+	client, resourceSpec, err := verber.getResourceSpecFromKind(kind, namespaceSet)
+	if err != nil {
+		return err
+	}
+
+	req := client.Put().
+		Resource(resourceSpec.Resource).
+		Name(name).
+		SetHeader("Content-Type", "application/json").
+		Body(object.Raw)
+	if resourceSpec.Namespaced {
+		req.Namespace(namespace)
+	}
+
+	return req.Do(context.TODO()).Error()
+}
+
+func (verber *resourceVerber) Get(kind string, namespaceSet bool, namespace string, name string) (runtime.Object,
+	error) {
+	client, resourceSpec, err := verber.getResourceSpecFromKind(kind, namespaceSet)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &runtime.Unknown{}
+	req := client.Get().
+		Resource(resourceSpec.Resource).
+		Name(name).
+		SetHeader("Accept", "application/json")
+	if resourceSpec.Namespaced {
+		req.Namespace(namespace)
+	}
+
+	if err = req.Do(context.TODO()).Into(result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (verber *resourceVerber) getResourceSpecFromKind(kind string, namespaceSet bool) (RESTClient, api.APIMapping,
+	error) {
+	resourceSpec, exists := api.KindToAPIMapping[kind]
+	if !exists {
+		return nil, resourceSpec, errors.NewBadRequest(fmt.Sprintf("unknown resource kind: %s", kind))
+	}
+
+	if namespaceSet != resourceSpec.Namespaced {
+		if namespaceSet {
+			return nil, resourceSpec, errors.NewBadRequest(
+				fmt.Sprintf("namespace set for cluster scoped resource kind: %s", kind))
+		}
+
+		return nil, resourceSpec, errors.NewBadRequest(fmt.Sprintf("namespace not set for namespaced resource kind: %s",
+			kind))
+	}
+
+	client := verber.getRESTClientByType(resourceSpec.ClientType)
+	if client == nil {
+		return nil, resourceSpec, errors.NewBadRequest(fmt.Sprintf("resource kind %s is not supported", kind))
+	}
+
+	return client, resourceSpec, nil
+}
+
+func (verber *resourceVerber) getRESTClientByType(clientType api.ClientType) RESTClient {
+	switch clientType {
+	case api.ClientTypeDefault:
+		return verber.client
+	case api.ClientTypeExtensionClient:
+		return verber.extensionsClient
+	case api.ClientTypeAppsClient:
+		return verber.appsClient
+	case api.ClientTypeBatchClient:
+		return verber.batchClient
+	case api.ClientTypeBetaBatchClient:
+		return verber.betaBatchClient
+	case api.ClientTypeAutoscalingClient:
+		return verber.autoscalingClient
+	case api.ClientTypeStorageClient:
+		return verber.storageClient
+	case api.ClientTypeRbacClient:
+		return verber.rbacClient
+	case api.ClientTypeAPIExtensionsClient:
+		return verber.apiExtensionsClient
+	case api.ClientTypeNetworkingClient:
+		return verber.networkingClient
+	}
+
+	// Plugins do not have a client yet.
+	return nil
+}
+
+// NewResourceVerber creates ResourceVerber that dispatches requests to the REST client of the resource kind.
+func NewResourceVerber(client, extensionsClient, appsClient, batchClient, betaBatchClient, autoscalingClient,
+	storageClient, rbacClient, apiExtensionsClient, networkingClient RESTClient) kubernetesapi.ResourceVerber {
+	return &resourceVerber{
+		client:              client,
+		extensionsClient:    extensionsClient,
+		appsClient:          appsClient,
+		batchClient:         batchClient,
+		betaBatchClient:     betaBatchClient,
+		autoscalingClient:   autoscalingClient,
+		storageClient:       storageClient,
+		rbacClient:          rbacClient,
+		apiExtensionsClient: apiExtensionsClient,
+		networkingClient:    networkingClient,
+	}
+}
@@ -0,0 +1,114 @@
+package kubernetes_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest/fake"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes"
+	kubernetesapi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
+)
+
+const deploymentJSON = `{"kind":"Deployment","apiVersion":"apps/v1","metadata":{"name":"foo","namespace":"bar"}}`
+
+func newFakeRESTClient() *fake.RESTClient {
+	return &fake.RESTClient{
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		Resp: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(deploymentJSON))),
+		},
+	}
+}
+
+func newFakeVerber() (kubernetesapi.ResourceVerber, map[string]*fake.RESTClient) {
+	clients := map[string]*fake.RESTClient{}
+	for _, name := range []string{"core", "extensions", "apps", "batch", "betaBatch", "autoscaling", "storage", "rbac",
+		"apiExtensions", "networking"} {
+		clients[name] = newFakeRESTClient()
+	}
+
+	return kubernetes.NewResourceVerber(clients["core"], clients["extensions"], clients["apps"], clients["batch"],
+		clients["betaBatch"], clients["autoscaling"], clients["storage"], clients["rbac"], clients["apiExtensions"],
+		clients["networking"]), clients
+}
+
+func TestResourceVerberGet(t *testing.T) {
+	verber, clients := newFakeVerber()
+	result, err := verber.Get("deployment", true, "bar", "foo")
+	if err != nil {
+		t.Fatalf("Get(): expected no error but got: %s", err)
+	}
+
+	if raw := string(result.(*runtime.Unknown).Raw); raw != deploymentJSON {
+		t.Errorf("Get(): expected %s but got %s", deploymentJSON, raw)
+	}
+
+	request := clients["apps"].Req
+	if request == nil || request.Method != http.MethodGet || request.URL.Path != "/namespaces/bar/deployments/foo" {
+		t.Errorf("Get(): expected GET of the deployment sent by the apps client but got %+v", request)
+	}
+}
+
+func TestResourceVerberDispatch(t *testing.T) {
+	cases := []struct {
+		kind         string
+		namespaceSet bool
+		client       string
+		path         string
+		method       string
+	}{
+		{"node", false, "core", "/nodes/foo", http.MethodDelete},
+		{"cronjob", true, "betaBatch", "/namespaces/bar/cronjobs/foo", http.MethodDelete},
+		{"ingress", true, "networking", "/namespaces/bar/ingresses/foo", http.MethodPut},
+		{"clusterrole", false, "rbac", "/clusterroles/foo", http.MethodPut},
+		{"customresourcedefinition", false, "apiExtensions", "/customresourcedefinitions/foo", http.MethodPut},
+	}
+
+	for _, c := range cases {
+		verber, clients := newFakeVerber()
+		var err error
+		if c.method == http.MethodDelete {
+			err = verber.Delete(c.kind, c.namespaceSet, "bar", "foo")
+		} else {
+			err = verber.Put(c.kind, c.namespaceSet, "bar", "foo", &runtime.Unknown{Raw: []byte("{}")})
+		}
+
+		if err != nil {
+			t.Errorf("%s %s: expected no error but got: %s", c.method, c.kind, err)
+			continue
+		}
+
+		request := clients[c.client].Req
+		if request == nil || request.Method != c.method || request.URL.Path != c.path {
+			t.Errorf("%s %s: expected request to %s sent by %s client but got %+v", c.method, c.kind, c.path,
+				c.client, request)
+		}
+	}
+}
+
+func TestResourceVerberInvalidKind(t *testing.T) {
+	cases := []struct {
+		kind         string
+		namespaceSet bool
+	}{
+		{"unknown", true},
+		{"deployment", false},
+		{"node", true},
+		{"plugin", true},
+	}
+
+	for _, c := range cases {
+		verber, _ := newFakeVerber()
+		if _, err := verber.Get(c.kind, c.namespaceSet, "bar", "foo"); !k8sErrors.IsBadRequest(err) {
+			t.Errorf("Get(%s, %t): expected bad request error but got %v", c.kind, c.namespaceSet, err)
+		}
+	}
+}
@@ -1,12 +1,22 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/donghoon-khan/kubeportal/src/app/backend/args"
 	"github.com/donghoon-khan/kubeportal/src/app/backend/auth"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/auth/jwe"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/auth/oidc"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/auth/pat"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/cert"
 	"github.com/donghoon-khan/kubeportal/src/app/backend/docs"
 	"github.com/donghoon-khan/kubeportal/src/app/backend/integration"
 
@@ -16,10 +26,79 @@ import (
 	k8sApi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
 )
 
+// Every flag can also be set with KUBEPORTAL_* environment variable, e.g. KUBEPORTAL_API_LOG_LEVEL, or in the
+// YAML config file given with --config.
+var (
+	argConfigFile = pflag.String("config", "",
+		"path to YAML config file, its keys are names of the flags. Flags and environment variables take precedence")
+	argPort          = pflag.Int("port", 9090, "port to listen to for incoming HTTP requests, 0 disables HTTP")
+	argApiServerHost = pflag.String("apiserver-host", "",
+		"address of the Kubernetes apiserver to connect to, e.g. http://127.0.0.1:8001. "+
+			"If neither this nor --kubeconfig is given, in-cluster config is used")
+	argKubeConfigFile     = pflag.String("kubeconfig", "", "path to kubeconfig file with authorization and master location")
+	argKubeConfigContexts = pflag.StringSlice("kubeconfig-context", nil,
+		"additional contexts of the kubeconfig file served as separate clusters, can be repeated")
+	argKubeConfigDir = pflag.String("kubeconfig-dir", "",
+		"directory of kubeconfig files, current context of every file is served as a separate cluster")
+	argApiLogLevel = pflag.String("api-log-level", "INFO", "level of API request logging, one of NONE, INFO or DEBUG")
+	argNamespace   = pflag.String("namespace", "default",
+		"namespace of the portal, secrets holding its keys are stored there")
+	argEnableSkipLogin    = pflag.Bool("enable-skip-login", false, "allow to use the portal without logging in")
+	argProtectedResources = pflag.StringSlice("protected-resource", nil,
+		"additional resource that can not be accessed through the portal, in kind/namespace/name or kind/name "+
+			"format, can be repeated")
+	argEncryptionKeyRotationInterval = pflag.Duration("encryption-key-rotation-interval", 24*time.Hour,
+		"age of the token encryption key after which it is replaced, users have to log in again after that, "+
+			"0 disables the rotation")
+
+	argLoginMaxAttemptsPerUser = pflag.Int("login-max-attempts-per-user", 5,
+		"failed logins of a user before further attempts are locked out for --login-lockout, 0 disables the limit")
+	argLoginMaxAttemptsPerIp = pflag.Int("login-max-attempts-per-ip", 20,
+		"failed logins from a client IP before further attempts are locked out for --login-lockout, 0 disables the limit")
+	argLoginBackoff = pflag.Duration("login-backoff", time.Second,
+		"time to wait after the first failed login, doubles with every next failure")
+	argLoginLockout = pflag.Duration("login-lockout", 15*time.Minute,
+		"time to wait after reaching the maximum of failed logins")
+	argLogStreamMaxLinesPerSecond = pflag.Int("log-stream-max-lines-per-second", 200,
+		"lines of followed logs sent to a single client per second, reading of faster logs is slowed down, "+
+			"0 disables the limit")
+
+	argSecurePort               = pflag.Int("secure-port", 8443, "port to listen to for incoming HTTPS requests")
+	argTlsCertFile              = pflag.String("tls-cert-file", "", "file containing the x509 certificate for HTTPS")
+	argTlsKeyFile               = pflag.String("tls-key-file", "", "file containing the x509 private key matching --tls-cert-file")
+	argAutoGenerateCertificates = pflag.Bool("auto-generate-certificates", false,
+		"serve HTTPS with self-signed certificate stored in the "+authApi.CertificateHolderSecretName+
+			" secret when --tls-cert-file and --tls-key-file are not given")
+	argRedirectToHttps = pflag.Bool("redirect-to-https", false,
+		"redirect HTTP requests to HTTPS instead of serving them, requires HTTPS to be enabled")
+	argTlsClientCaFile = pflag.String("tls-client-ca-file", "",
+		"file containing CAs client certificates are verified against")
+	argTlsRequireClientCert = pflag.Bool("tls-require-client-cert", false,
+		"refuse HTTPS clients without a certificate signed by --tls-client-ca-file")
+	argTlsClientCertGroups = pflag.StringSlice("tls-client-cert-group", nil,
+		"organization of client certificates passed to the apiserver as a group, other organizations are ignored; "+
+			"system: groups are never passed")
+
+	argOidcIssuerUrl      = pflag.String("oidc-issuer-url", "", "URL of the OpenID Connect issuer, enables OIDC login")
+	argOidcClientId       = pflag.String("oidc-client-id", "", "client ID that ID tokens must be issued for")
+	argOidcJwksFile       = pflag.String("oidc-jwks-file", "", "path to key set of the OpenID Connect issuer")
+	argOidcJwksUrl        = pflag.String("oidc-jwks-url", "", "URL of key set of the OpenID Connect issuer")
+	argOidcUsernameClaim  = pflag.String("oidc-username-claim", oidc.DefaultUsernameClaim, "ID token claim used as user name")
+	argOidcUsernamePrefix = pflag.String("oidc-username-prefix", "", "prefix prepended to user names")
+	argOidcGroupsClaim    = pflag.String("oidc-groups-claim", oidc.DefaultGroupsClaim, "ID token claim used as groups")
+	argOidcGroupsPrefix   = pflag.String("oidc-groups-prefix", "", "prefix prepended to group names")
+	argOidcImpersonate    = pflag.Bool("oidc-impersonate", false,
+		"impersonate OIDC users with the portal's credentials instead of passing ID tokens to the apiserver, "+
+			"system: users and groups are never impersonated")
+)
+
 func main() {
 	log.SetOutput(os.Stdout)
 	initArgHolder()
 
+	if *argConfigFile != "" {
+		log.Printf("Using config file: %s", *argConfigFile)
+	}
 	if args.Holder.GetApiServerHost() != "" {
 		log.Printf("Using apiserver-host: %s", args.Holder.GetApiServerHost())
 	}
@@ -29,17 +108,23 @@ func main() {
 	if args.Holder.GetNamespace() != "" {
 		log.Printf("Using namespace: %s", args.Holder.GetNamespace())
 	}
-	k8sManager := kubernetes.NewKubernetesManager(args.Holder.GetKubeConfigFile(), args.Holder.GetApiServerHost())
+	if args.Holder.GetKubeConfigDir() != "" {
+		log.Printf("Using kubeconfig directory: %s", args.Holder.GetKubeConfigDir())
+	}
+	k8sManager := kubernetes.NewMultiClusterKubernetesManager(args.Holder.GetKubeConfigFile(),
+		args.Holder.GetApiServerHost(), args.Holder.GetKubeConfigContexts(), args.Holder.GetKubeConfigDir())
 	versionInfo, err := k8sManager.InsecureKubernetes().Discovery().ServerVersion()
 	if err != nil {
 		handleFatalInitError(err)
 	}
 	log.Printf("Successful initial request to the apiserver, version: %s", versionInfo.String())
 
-	authManager := initAuthManager(k8sManager)
+	patManager := pat.NewPersonalAccessTokenManager(k8sManager.InsecureKubernetes(), args.Holder.GetNamespace())
+	k8sManager.SetPersonalAccessTokenManager(patManager)
+	authManager := initAuthManager(k8sManager, patManager)
 	iManager := integration.NewIntegrationManager(k8sManager)
 
-	apiHandler, err := handler.CreateHttpApiHandler(iManager, k8sManager, authManager)
+	apiHandler, err := handler.CreateHttpApiHandler(iManager, k8sManager, authManager, patManager)
 	if err != nil {
 		handleFatalInitError(err)
 	}
@@ -47,25 +132,139 @@ func main() {
 	http.Handle("/api/", apiHandler)
 	http.Handle("/docs", docs.CreateApiDocsHTTPHandler(apiHandler, "/apidocs.json", nil))
 
-	go func() { log.Fatal(http.ListenAndServe(":9090", nil)) }()
+	serve(k8sManager)
 	select {}
 }
 
-func initAuthManager(k8sManager k8sApi.KubernetesManager) authApi.AuthManager {
+// serve starts HTTPS server when certificate is given or auto-generation is enabled, and HTTP server that either
+// serves the same handlers or redirects to HTTPS.
+func serve(k8sManager k8sApi.KubernetesManager) {
+	tlsEnabled := args.Holder.GetAutoGenerateCertificates() ||
+		(args.Holder.GetTlsCertFile() != "" && args.Holder.GetTlsKeyFile() != "")
+	if tlsEnabled {
+		certificate, err := cert.NewCertificateManager(args.Holder.GetTlsCertFile(), args.Holder.GetTlsKeyFile(),
+			k8sManager.InsecureKubernetes(), args.Holder.GetNamespace()).Certificate()
+		if err != nil {
+			log.Fatalf("Error while loading certificate. Reason: %s", err)
+		}
 
-	return auth.NewAuthManager(k8sManager,
-		authApi.AuthenticationModes{authApi.Token: true, authApi.Basic: true},
-		true)
+		tlsConfig, err := cert.NewServerTLSConfig(certificate, args.Holder.GetTlsClientCaFile(),
+			args.Holder.GetTlsRequireClientCert())
+		if err != nil {
+			log.Fatalf("Error while configuring TLS. Reason: %s", err)
+		}
 
+		server := &http.Server{Addr: fmt.Sprintf(":%d", args.Holder.GetSecurePort()), TLSConfig: tlsConfig}
+		log.Printf("Serving securely on HTTPS port: %d", args.Holder.GetSecurePort())
+		go func() { log.Fatal(server.ListenAndServeTLS("", "")) }()
+	} else if args.Holder.GetRedirectToHttps() {
+		log.Fatal("Redirect to HTTPS requires --tls-cert-file and --tls-key-file or --auto-generate-certificates")
+	}
+
+	if args.Holder.GetPort() == 0 {
+		return
+	}
+
+	var httpHandler http.Handler
+	if args.Holder.GetRedirectToHttps() {
+		httpHandler = handler.NewHTTPSRedirectHandler(args.Holder.GetSecurePort())
+		log.Printf("Redirecting HTTP port %d to HTTPS", args.Holder.GetPort())
+	} else {
+		log.Printf("Serving insecurely on HTTP port: %d", args.Holder.GetPort())
+	}
+
+	go func() { log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", args.Holder.GetPort()), httpHandler)) }()
+}
+
+func initAuthManager(k8sManager k8sApi.KubernetesManager,
+	patManager authApi.PersonalAccessTokenManager) authApi.AuthManager {
+	keyHolder := jwe.NewRSAKeyHolder(k8sManager.InsecureKubernetes(), args.Holder.GetNamespace())
+	if interval := args.Holder.GetEncryptionKeyRotationInterval(); interval > 0 {
+		jwe.StartRotation(keyHolder, interval, wait.NeverStop)
+	}
+	revocationStore := jwe.NewSecretRevocationStore(k8sManager.InsecureKubernetes(), args.Holder.GetNamespace())
+	tokenManager := jwe.NewRevocableJWETokenManager(keyHolder, revocationStore)
+	tokenManager.SetTokenTTL(authApi.DefaultTokenTTL * time.Second)
+	k8sManager.SetTokenManager(tokenManager)
+
+	authModes := authApi.AuthenticationModes{authApi.Token: true, authApi.Basic: true}
+	idTokenVerifier := initIDTokenVerifier()
+	if idTokenVerifier != nil {
+		authModes.Add(authApi.OIDC)
+	}
+
+	// Client certificates are verified only when the client CA is configured.
+	if args.Holder.GetTlsClientCaFile() != "" {
+		authModes.Add(authApi.ClientCertificate)
+	}
+
+	return auth.NewAuthManager(k8sManager, tokenManager, patManager, idTokenVerifier, authModes,
+		args.Holder.GetEnableSkipLogin())
+}
+
+func initIDTokenVerifier() authApi.IDTokenVerifier {
+	if args.Holder.GetOidcIssuerUrl() == "" {
+		return nil
+	}
+
+	verifier, err := oidc.NewIDTokenVerifier(oidc.Config{
+		IssuerURL:      args.Holder.GetOidcIssuerUrl(),
+		ClientID:       args.Holder.GetOidcClientId(),
+		JWKSFile:       args.Holder.GetOidcJwksFile(),
+		JWKSURL:        args.Holder.GetOidcJwksUrl(),
+		UsernameClaim:  args.Holder.GetOidcUsernameClaim(),
+		UsernamePrefix: args.Holder.GetOidcUsernamePrefix(),
+		GroupsClaim:    args.Holder.GetOidcGroupsClaim(),
+		GroupsPrefix:   args.Holder.GetOidcGroupsPrefix(),
+		Impersonate:    args.Holder.GetOidcImpersonate(),
+	})
+	if err != nil {
+		log.Fatalf("Error while initializing OIDC authentication. Reason: %s", err)
+	}
+
+	log.Printf("Using OIDC issuer: %s", args.Holder.GetOidcIssuerUrl())
+	return verifier
 }
 
 func initArgHolder() {
+	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
+	if err := args.Parse(pflag.CommandLine, os.Args[1:], "config"); err != nil {
+		log.Fatalf("Error while parsing configuration. Reason: %s", err)
+	}
+
 	builder := args.GetHolderBuilder()
-	builder.SetApiServerHost("http://127.0.0.1:8001")
-	builder.SetApiLogLevel("INFO")
-	builder.SetKubeConfigFile("kube.config")
-	builder.SetNamespace("default")
-	builder.SetPort(9090)
+	builder.SetApiServerHost(*argApiServerHost)
+	builder.SetApiLogLevel(*argApiLogLevel)
+	builder.SetKubeConfigFile(*argKubeConfigFile)
+	builder.SetKubeConfigContexts(*argKubeConfigContexts)
+	builder.SetKubeConfigDir(*argKubeConfigDir)
+	builder.SetNamespace(*argNamespace)
+	builder.SetPort(*argPort)
+	builder.SetSecurePort(*argSecurePort)
+	builder.SetTlsCertFile(*argTlsCertFile)
+	builder.SetTlsKeyFile(*argTlsKeyFile)
+	builder.SetAutoGenerateCertificates(*argAutoGenerateCertificates)
+	builder.SetRedirectToHttps(*argRedirectToHttps)
+	builder.SetTlsClientCaFile(*argTlsClientCaFile)
+	builder.SetTlsRequireClientCert(*argTlsRequireClientCert)
+	builder.SetTlsClientCertGroups(*argTlsClientCertGroups)
+	builder.SetEnableSkipLogin(*argEnableSkipLogin)
+	builder.SetProtectedResources(*argProtectedResources)
+	builder.SetEncryptionKeyRotationInterval(*argEncryptionKeyRotationInterval)
+	builder.SetLoginMaxAttemptsPerUser(*argLoginMaxAttemptsPerUser)
+	builder.SetLoginMaxAttemptsPerIp(*argLoginMaxAttemptsPerIp)
+	builder.SetLoginBackoff(*argLoginBackoff)
+	builder.SetLoginLockout(*argLoginLockout)
+	builder.SetLogStreamMaxLinesPerSecond(*argLogStreamMaxLinesPerSecond)
+	builder.SetOidcIssuerUrl(*argOidcIssuerUrl)
+	builder.SetOidcClientId(*argOidcClientId)
+	builder.SetOidcJwksFile(*argOidcJwksFile)
+	builder.SetOidcJwksUrl(*argOidcJwksUrl)
+	builder.SetOidcUsernameClaim(*argOidcUsernameClaim)
+	builder.SetOidcUsernamePrefix(*argOidcUsernamePrefix)
+	builder.SetOidcGroupsClaim(*argOidcGroupsClaim)
+	builder.SetOidcGroupsPrefix(*argOidcGroupsPrefix)
+	builder.SetOidcImpersonate(*argOidcImpersonate)
 }
 
 func handleFatalInitError(err error) {
@@ -0,0 +1,56 @@
+package apiresource
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	k8sApi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/dataselect"
+)
+
+// CoreGroup is used in paths instead of the empty name of the core API group.
+const CoreGroup = "core"
+
+type ResourceCell unstructured.Unstructured
+
+func (self ResourceCell) GetProperty(name dataselect.PropertyName) dataselect.ComparableValue {
+	object := unstructured.Unstructured(self)
+	switch name {
+	case dataselect.NameProperty:
+		return dataselect.StdComparableString(object.GetName())
+	case dataselect.CreationTimestampProperty:
+		return dataselect.StdComparableTime(object.GetCreationTimestamp().Time)
+	case dataselect.NamespaceProperty:
+		return dataselect.StdComparableString(object.GetNamespace())
+	default:
+		return nil
+	}
+}
+
+func toCells(std []unstructured.Unstructured) []dataselect.DataCell {
+	cells := make([]dataselect.DataCell, len(std))
+	for i := range std {
+		cells[i] = ResourceCell(std[i])
+	}
+	return cells
+}
+
+func fromCells(cells []dataselect.DataCell) []unstructured.Unstructured {
+	std := make([]unstructured.Unstructured, len(cells))
+	for i := range std {
+		std[i] = unstructured.Unstructured(cells[i].(ResourceCell))
+	}
+	return std
+}
+
+// ToGroup returns name of the API group given in a path, i.e. empty name for the CoreGroup.
+func ToGroup(group string) string {
+	if group == CoreGroup {
+		return ""
+	}
+	return group
+}
+
+func toGroupVersionResource(resource k8sApi.APIResource) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: resource.Group, Version: resource.Version, Resource: resource.Resource}
+}
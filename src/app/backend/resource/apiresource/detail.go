@@ -0,0 +1,37 @@
+package apiresource
+
+import (
+	"context"
+	"log"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	k8sApi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
+)
+
+type ResourceDetail struct {
+	Resource `json:",inline"`
+	Object   map[string]interface{} `json:"object"`
+}
+
+func GetResourceDetail(client dynamic.Interface, resource k8sApi.APIResource, namespace,
+	name string) (*ResourceDetail, error) {
+	log.Printf("Getting details of %s %s in %s namespace", toGroupVersionResource(resource), name, namespace)
+
+	resourceClient := client.Resource(toGroupVersionResource(resource))
+	var object *unstructured.Unstructured
+	var err error
+	if resource.Namespaced {
+		object, err = resourceClient.Namespace(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	} else {
+		object, err = resourceClient.Get(context.TODO(), name, metaV1.GetOptions{})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResourceDetail{Resource: toResource(resource, *object), Object: object.Object}, nil
+}
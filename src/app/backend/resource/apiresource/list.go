@@ -0,0 +1,91 @@
+package apiresource
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+	k8sApi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/common"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/dataselect"
+)
+
+type ResourceList struct {
+	ListMeta    api.ListMeta       `json:"listMeta"`
+	APIResource k8sApi.APIResource `json:"apiResource"`
+	Items       []Resource         `json:"items"`
+	Errors      []error            `json:"errors"`
+}
+
+type Resource struct {
+	ObjectMeta api.ObjectMeta `json:"objectMeta"`
+	TypeMeta   api.TypeMeta   `json:"typeMeta"`
+}
+
+// GetResourceList returns objects of any resource served by the cluster. Namespace query is ignored for cluster
+// scoped resources.
+func GetResourceList(client dynamic.Interface, resource k8sApi.APIResource, nsQuery *common.NamespaceQuery,
+	dsQuery *dataselect.DataSelectQuery) (*ResourceList, error) {
+	log.Printf("Getting list of %s in the namespace %s", toGroupVersionResource(resource), nsQuery.ToRequestParam())
+	resourceClient := client.Resource(toGroupVersionResource(resource))
+
+	var list *unstructured.UnstructuredList
+	var err error
+	if resource.Namespaced {
+		list, err = resourceClient.Namespace(nsQuery.ToRequestParam()).List(context.TODO(), api.ListEverything)
+	} else {
+		list, err = resourceClient.List(context.TODO(), api.ListEverything)
+	}
+
+	nonCriticalErrors, criticalError := errors.HandleError(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	items := make([]unstructured.Unstructured, 0)
+	if list != nil {
+		for _, item := range list.Items {
+			if !resource.Namespaced || nsQuery.Matches(item.GetNamespace()) {
+				items = append(items, item)
+			}
+		}
+	}
+
+	return toResourceList(resource, items, nonCriticalErrors, dsQuery), nil
+}
+
+func toResource(resource k8sApi.APIResource, object unstructured.Unstructured) Resource {
+	return Resource{
+		ObjectMeta: api.ObjectMeta{
+			Name:              object.GetName(),
+			Namespace:         object.GetNamespace(),
+			Labels:            object.GetLabels(),
+			Annotations:       object.GetAnnotations(),
+			CreationTimestamp: object.GetCreationTimestamp(),
+			UID:               object.GetUID(),
+		},
+		TypeMeta: api.NewTypeMeta(api.ResourceKind(strings.ToLower(resource.Kind))),
+	}
+}
+
+func toResourceList(resource k8sApi.APIResource, objects []unstructured.Unstructured, nonCriticalErrors []error,
+	dsQuery *dataselect.DataSelectQuery) *ResourceList {
+	result := &ResourceList{
+		APIResource: resource,
+		Items:       make([]Resource, 0),
+		Errors:      nonCriticalErrors,
+	}
+
+	cells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(objects), dsQuery)
+	result.ListMeta = api.ListMeta{TotalItems: filteredTotal}
+	for _, object := range fromCells(cells) {
+		result.Items = append(result.Items, toResource(resource, object))
+	}
+
+	return result
+}
@@ -0,0 +1,71 @@
+package apiresource
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/api"
+	k8sApi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/common"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/dataselect"
+)
+
+var widgets = k8sApi.APIResource{
+	Group:      "example.com",
+	Version:    "v1alpha1",
+	Resource:   "widgets",
+	Kind:       "Widget",
+	Namespaced: true,
+}
+
+func newWidget(namespace, name string) *unstructured.Unstructured {
+	widget := &unstructured.Unstructured{}
+	widget.SetAPIVersion("example.com/v1alpha1")
+	widget.SetKind("Widget")
+	widget.SetNamespace(namespace)
+	widget.SetName(name)
+	return widget
+}
+
+func TestGetResourceList(t *testing.T) {
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{toGroupVersionResource(widgets): "WidgetList"},
+		newWidget("foo", "b"), newWidget("foo", "a"), newWidget("bar", "c"))
+
+	cases := []struct {
+		namespace *common.NamespaceQuery
+		expected  []string
+	}{
+		{common.NewNamespaceQuery(nil), []string{"a", "b", "c"}},
+		{common.NewSameNamespaceQuery("foo"), []string{"a", "b"}},
+		{common.NewNamespaceQuery([]string{"bar", "baz"}), []string{"c"}},
+	}
+
+	dsQuery := dataselect.NewDataSelectQuery(dataselect.NoPagination, dataselect.NewSortQuery([]string{"asc", "name"}),
+		dataselect.NoFilter, dataselect.NoMetrics)
+	for _, c := range cases {
+		actual, err := GetResourceList(client, widgets, c.namespace, dsQuery)
+		if err != nil {
+			t.Fatalf("GetResourceList(): expected no error but got: %s", err)
+		}
+
+		names := make([]string, 0)
+		for _, item := range actual.Items {
+			names = append(names, item.ObjectMeta.Name)
+		}
+
+		if !reflect.DeepEqual(names, c.expected) || actual.ListMeta.TotalItems != len(c.expected) {
+			t.Errorf("GetResourceList(%s): expected %v but got %v", c.namespace.ToRequestParam(), c.expected,
+				names)
+		}
+
+		if len(actual.Items) > 0 && actual.Items[0].TypeMeta.Kind != api.ResourceKind("widget") {
+			t.Errorf("GetResourceList(): expected widget kind but got %s", actual.Items[0].TypeMeta.Kind)
+		}
+	}
+}
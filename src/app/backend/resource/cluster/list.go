@@ -0,0 +1,93 @@
+package cluster
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/version"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/api"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+	k8sApi "github.com/donghoon-khan/kubeportal/src/app/backend/kubernetes/api"
+)
+
+// Time given to every cluster to report its version before it is considered unreachable.
+const versionTimeout = 5 * time.Second
+
+var errTimeout = errors.NewInternal("timed out waiting for the cluster version")
+
+type ClusterList struct {
+	ListMeta api.ListMeta `json:"listMeta"`
+	Clusters []Cluster    `json:"clusters"`
+}
+
+type Cluster struct {
+	Name      string `json:"name"`
+	Default   bool   `json:"default"`
+	Reachable bool   `json:"reachable"`
+	Version   string `json:"version,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// GetClusterList returns clusters served by the portal. Versions are read with the backend's own credentials
+// from all clusters at once.
+func GetClusterList(manager k8sApi.KubernetesManager) *ClusterList {
+	names := manager.Clusters()
+	result := &ClusterList{
+		ListMeta: api.ListMeta{TotalItems: len(names)},
+		Clusters: make([]Cluster, len(names)),
+	}
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			result.Clusters[i] = getCluster(manager, name)
+		}(i, name)
+	}
+
+	wg.Wait()
+	return result
+}
+
+func getCluster(manager k8sApi.KubernetesManager, name string) Cluster {
+	result := Cluster{Name: name, Default: name == k8sApi.DefaultClusterName}
+	versionInfo, err := getVersion(manager, name)
+	if err != nil {
+		log.Printf("Cluster %s is not reachable. Reason: %s", name, err)
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Reachable = true
+	result.Version = versionInfo.GitVersion
+	return result
+}
+
+func getVersion(manager k8sApi.KubernetesManager, name string) (*version.Info, error) {
+	k8sClient, err := manager.InsecureClusterKubernetes(name)
+	if err != nil {
+		return nil, err
+	}
+
+	type versionResult struct {
+		info *version.Info
+		err  error
+	}
+
+	// Buffered, so that the request can finish after the timeout without blocking.
+	resultChan := make(chan versionResult, 1)
+	go func() {
+		info, err := k8sClient.Discovery().ServerVersion()
+		resultChan <- versionResult{info, err}
+	}()
+
+	select {
+	case result := <-resultChan:
+		return result.info, result.err
+	case <-time.After(versionTimeout):
+		return nil, errTimeout
+	}
+}
@@ -0,0 +1,160 @@
+package container
+
+import (
+	"sort"
+	"sync"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/logs"
+)
+
+const (
+	// Logs of at most this number of containers are read at the same time.
+	maxConcurrentLogReads = 10
+	// Logs of at most this number of containers are aggregated, logs of the other ones are left out as if the read
+	// limit was reached.
+	maxAggregatedLogSources = 50
+	// Limits of all logs read for a single aggregation, split evenly among the containers. No container gets more
+	// than byteReadLimit and lineReadLimit.
+	aggregatedByteReadLimit int64 = 5000000
+	aggregatedLineReadLimit int64 = 50000
+)
+
+type logSource struct {
+	pod       string
+	container string
+}
+
+type readLimits struct {
+	bytes int64
+	lines int64
+}
+
+// aggregatedReadLimits returns limits of logs read from each of the given number of containers.
+func aggregatedReadLimits(sources int) readLimits {
+	result := readLimits{bytes: byteReadLimit, lines: lineReadLimit}
+	if sources == 0 {
+		return result
+	}
+
+	if perSource := aggregatedByteReadLimit / int64(sources); perSource < result.bytes {
+		result.bytes = perSource
+	}
+
+	if perSource := aggregatedLineReadLimit / int64(sources); perSource < result.lines {
+		result.lines = perSource
+	}
+
+	return result
+}
+
+func (self readLimits) reached(bytesLoaded, linesLoaded int64, logFilePosition string) bool {
+	return bytesLoaded >= self.bytes || (logFilePosition == logs.End && linesLoaded >= self.lines)
+}
+
+type readLogsFunc func(source logSource, limits readLimits) (string, error)
+
+// GetAggregatedLogDetail returns the selected lines of logs of all containers in all Pods of the resource, e.g.
+// a Job or a ReplicaSet. Lines are merged by their timestamps and tagged with the Pod and the container.
+func GetAggregatedLogDetail(kubernetes kubernetes.Interface, ns, resourceName, resourceType string,
+	logSelector *logs.Selection, structured *logs.StructuredQuery, usePreviousLogs bool) (*logs.LogDetails, error) {
+	logSources, err := logs.GetLogSources(kubernetes, ns, resourceName, resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]logSource, 0)
+	for _, pod := range logSources.PodNames {
+		for _, container := range logSources.ContainerNames {
+			sources = append(sources, logSource{pod: pod, container: container})
+		}
+	}
+
+	parsedLines, readLimitReached, err := readAggregatedLogs(sources, logSelector.LogFilePosition,
+		func(source logSource, limits readLimits) (string, error) {
+			// Bytes are limited also when reading from the end, so that long lines do not exceed the limits.
+			logOptions := mapToLogOptions(source.container, logSelector, usePreviousLogs)
+			logOptions.LimitBytes = &limits.bytes
+			if logOptions.TailLines != nil {
+				logOptions.TailLines = &limits.lines
+			}
+
+			return readRawLogs(kubernetes, ns, source.pod, logOptions)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	if structured != nil {
+		parsedLines = parsedLines.Structured(structured)
+	}
+
+	logLines, fromDate, toDate, logSelection, lastPage := parsedLines.SelectLogs(logSelector)
+	return &logs.LogDetails{
+		Info: logs.LogInfo{
+			FromDate:  fromDate,
+			ToDate:    toDate,
+			Truncated: readLimitReached && lastPage,
+		},
+		Selection: logSelection,
+		LogLines:  logLines,
+	}, nil
+}
+
+// readAggregatedLogs reads logs of the sources concurrently and merges them. Kubelet writes timestamps in a
+// fixed width format, so lines are ordered by comparing them as strings, same as when selecting lines. Lines
+// with equal timestamps keep the order of the sources.
+func readAggregatedLogs(sources []logSource, logFilePosition string, read readLogsFunc) (logs.LogLines, bool,
+	error) {
+	sourcesLimitReached := len(sources) > maxAggregatedLogSources
+	if sourcesLimitReached {
+		sources = sources[:maxAggregatedLogSources]
+	}
+
+	limits := aggregatedReadLimits(len(sources))
+	results := make([]logs.LogLines, len(sources))
+	limitsReached := make([]bool, len(sources))
+	errs := make([]error, len(sources))
+	semaphore := make(chan struct{}, maxConcurrentLogReads)
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		wg.Add(1)
+		go func(i int, source logSource) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			rawLogs, err := read(source, limits)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+
+			lines := logs.ToLogLines(rawLogs)
+			for j := range lines {
+				lines[j].PodName = source.pod
+				lines[j].ContainerName = source.container
+			}
+			results[i] = lines
+			limitsReached[i] = limits.reached(int64(len(rawLogs)), int64(len(lines)), logFilePosition)
+		}(i, source)
+	}
+	wg.Wait()
+
+	merged := logs.LogLines{}
+	readLimitReached := sourcesLimitReached
+	for i := range sources {
+		if errs[i] != nil {
+			return nil, false, errs[i]
+		}
+
+		merged = append(merged, results[i]...)
+		readLimitReached = readLimitReached || limitsReached[i]
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Timestamp < merged[j].Timestamp
+	})
+	return merged, readLimitReached, nil
+}
@@ -0,0 +1,114 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/logs"
+)
+
+func TestReadAggregatedLogs(t *testing.T) {
+	rawLogs := map[logSource]string{
+		{"pod-1", "app"}:     "2021-01-01T00:00:01.000000000Z a\n2021-01-01T00:00:03.000000000Z c\n",
+		{"pod-1", "sidecar"}: "2021-01-01T00:00:02.000000000Z b\n",
+		{"pod-2", "app"}:     "2021-01-01T00:00:01.000000000Z d\n2021-01-01T00:00:04.000000000Z e\n",
+	}
+	sources := []logSource{{"pod-1", "app"}, {"pod-1", "sidecar"}, {"pod-2", "app"}}
+
+	result, readLimitReached, err := readAggregatedLogs(sources, logs.End, func(source logSource, limits readLimits) (string, error) {
+		return rawLogs[source], nil
+	})
+	if err != nil {
+		t.Fatalf("readAggregatedLogs(): expected no error but got: %s", err)
+	}
+
+	expected := logs.LogLines{
+		{Timestamp: "2021-01-01T00:00:01.000000000Z", Content: "a", PodName: "pod-1", ContainerName: "app"},
+		{Timestamp: "2021-01-01T00:00:01.000000000Z", Content: "d", PodName: "pod-2", ContainerName: "app"},
+		{Timestamp: "2021-01-01T00:00:02.000000000Z", Content: "b", PodName: "pod-1", ContainerName: "sidecar"},
+		{Timestamp: "2021-01-01T00:00:03.000000000Z", Content: "c", PodName: "pod-1", ContainerName: "app"},
+		{Timestamp: "2021-01-01T00:00:04.000000000Z", Content: "e", PodName: "pod-2", ContainerName: "app"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("readAggregatedLogs() == %#v, expected %#v", result, expected)
+	}
+
+	if readLimitReached {
+		t.Errorf("readAggregatedLogs(): expected read limit not to be reached")
+	}
+
+	selected, _, _, _, _ := result.SelectLogs(&logs.Selection{
+		ReferencePoint:  logs.LogLineId{LogTimestamp: "2021-01-01T00:00:01.000000000Z", LineNum: 2},
+		OffsetFrom:      0,
+		OffsetTo:        2,
+		LogFilePosition: logs.End,
+	})
+	if !reflect.DeepEqual(selected, expected[1:3]) {
+		t.Errorf("SelectLogs() == %#v, expected %#v", selected, expected[1:3])
+	}
+}
+
+func TestReadAggregatedLogsError(t *testing.T) {
+	expected := errors.New("forbidden")
+	sources := []logSource{{"pod-1", "app"}, {"pod-2", "app"}}
+	_, _, err := readAggregatedLogs(sources, logs.End, func(source logSource, limits readLimits) (string, error) {
+		if source.pod == "pod-2" {
+			return "", expected
+		}
+		return "", nil
+	})
+	if err != expected {
+		t.Errorf("readAggregatedLogs(): expected %s but got %v", expected, err)
+	}
+}
+
+func TestReadAggregatedLogsLimits(t *testing.T) {
+	sources := make([]logSource, 0)
+	for i := 0; i < maxAggregatedLogSources+10; i++ {
+		sources = append(sources, logSource{pod: fmt.Sprintf("pod-%d", i), container: "app"})
+	}
+
+	var mux sync.Mutex
+	read := map[logSource]readLimits{}
+	_, readLimitReached, err := readAggregatedLogs(sources, logs.End, func(source logSource,
+		limits readLimits) (string, error) {
+		mux.Lock()
+		defer mux.Unlock()
+		read[source] = limits
+		return "2021-01-01T00:00:01.000000000Z a\n", nil
+	})
+	if err != nil {
+		t.Fatalf("readAggregatedLogs(): expected no error but got: %s", err)
+	}
+
+	if len(read) != maxAggregatedLogSources || !readLimitReached {
+		t.Errorf("readAggregatedLogs(): expected logs of %d sources to be read with read limit reached but got %d, %t",
+			maxAggregatedLogSources, len(read), readLimitReached)
+	}
+
+	expected := readLimits{bytes: aggregatedByteReadLimit / maxAggregatedLogSources,
+		lines: aggregatedLineReadLimit / maxAggregatedLogSources}
+	if limits := read[sources[0]]; limits != expected {
+		t.Errorf("readAggregatedLogs(): expected limits %+v but got %+v", expected, limits)
+	}
+}
+
+func TestAggregatedReadLimits(t *testing.T) {
+	cases := []struct {
+		sources  int
+		expected readLimits
+	}{
+		{0, readLimits{byteReadLimit, lineReadLimit}},
+		{1, readLimits{byteReadLimit, lineReadLimit}},
+		{20, readLimits{aggregatedByteReadLimit / 20, aggregatedLineReadLimit / 20}},
+	}
+
+	for _, c := range cases {
+		if actual := aggregatedReadLimits(c.sources); actual != c.expected {
+			t.Errorf("aggregatedReadLimits(%d) == %+v, expected %+v", c.sources, actual, c.expected)
+		}
+	}
+}
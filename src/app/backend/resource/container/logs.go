@@ -33,8 +33,10 @@ func GetPodContainers(kubernetes kubernetes.Interface, ns, podID string) (*PodCo
 	return containers, nil
 }
 
+// GetLogDetail returns the selected lines of the container logs. When structured query is given, JSON and logfmt
+// lines are parsed and lines not matching the query are left out before selecting.
 func GetLogDetail(kubernetes kubernetes.Interface, ns, podID, container string,
-	logSelector *logs.Selection, usePreviousLogs bool) (*logs.LogDetails, error) {
+	logSelector *logs.Selection, structured *logs.StructuredQuery, usePreviousLogs bool) (*logs.LogDetails, error) {
 	pod, err := kubernetes.CoreV1().Pods(ns).Get(context.TODO(), podID, metaV1.GetOptions{})
 	if err != nil {
 		return nil, err
@@ -48,7 +50,7 @@ func GetLogDetail(kubernetes kubernetes.Interface, ns, podID, container string,
 	if err != nil {
 		return nil, err
 	}
-	details := ConstructLogDetails(podID, rawLogs, container, logSelector)
+	details := ConstructLogDetails(podID, rawLogs, container, logSelector, structured)
 	return details, nil
 }
 
@@ -59,13 +61,18 @@ func GetLogFile(kubernetes kubernetes.Interface, ns, podID, container string, us
 		Previous:   usePreviousLogs,
 		Timestamps: false,
 	}
-	logStream, err := openStream(kubernetes, ns, podID, logOptions)
+	logStream, err := openStream(context.TODO(), kubernetes, ns, podID, logOptions)
 	return logStream, err
 }
 
-func ConstructLogDetails(podID, rawLogs, container string, logSelector *logs.Selection) *logs.LogDetails {
+func ConstructLogDetails(podID, rawLogs, container string, logSelector *logs.Selection,
+	structured *logs.StructuredQuery) *logs.LogDetails {
 	parsedLines := logs.ToLogLines(rawLogs)
-	logLines, fromDate, toDate, logSelection, lastPage := parsedLines.SelectLogs(logSelector)
+	selectedLines := parsedLines
+	if structured != nil {
+		selectedLines = parsedLines.Structured(structured)
+	}
+	logLines, fromDate, toDate, logSelection, lastPage := selectedLines.SelectLogs(logSelector)
 
 	readLimitReached := isReadLimitReached(int64(len(rawLogs)), int64(len(parsedLines)), logSelector.LogFilePosition)
 	truncated := readLimitReached && lastPage
@@ -103,7 +110,7 @@ func mapToLogOptions(container string, logSelector *logs.Selection, previous boo
 
 func readRawLogs(kubernetes kubernetes.Interface, ns, podID string, logOptions *v1.PodLogOptions) (
 	string, error) {
-	readCloser, err := openStream(kubernetes, ns, podID, logOptions)
+	readCloser, err := openStream(context.TODO(), kubernetes, ns, podID, logOptions)
 	if err != nil {
 		return err.Error(), nil
 	}
@@ -118,16 +125,53 @@ func readRawLogs(kubernetes kubernetes.Interface, ns, podID string, logOptions *
 	return string(result), nil
 }
 
-func openStream(kubernetes kubernetes.Interface, ns, podID string, logOptions *v1.PodLogOptions) (io.ReadCloser, error) {
+func openStream(ctx context.Context, kubernetes kubernetes.Interface, ns, podID string,
+	logOptions *v1.PodLogOptions) (io.ReadCloser, error) {
 	return kubernetes.CoreV1().RESTClient().Get().
 		Namespace(ns).
 		Name(podID).
 		Resource("pods").
 		SubResource("log").
-		VersionedParams(logOptions, scheme.ParameterCodec).Stream(context.TODO())
+		VersionedParams(logOptions, scheme.ParameterCodec).Stream(ctx)
 }
 
 func isReadLimitReached(bytesLoaded int64, linesLoaded int64, logFilePosition string) bool {
 	return (logFilePosition == logs.Beginning && bytesLoaded >= byteReadLimit) ||
 		(logFilePosition == logs.End && linesLoaded >= lineReadLimit)
 }
+
+// SearchLogs returns lines of the container logs matching the query. Logs are read up to the same limits as when
+// selecting lines, from the beginning or the end of the log file.
+func SearchLogs(kubernetes kubernetes.Interface, ns, podID, container string, query *logs.SearchQuery,
+	logFilePosition string, usePreviousLogs bool) (*logs.LogSearchResult, error) {
+	pod, err := kubernetes.CoreV1().Pods(ns).Get(context.TODO(), podID, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(container) == 0 {
+		container = pod.Spec.Containers[0].Name
+	}
+	if logFilePosition != logs.Beginning {
+		logFilePosition = logs.End
+	}
+	logOptions := mapToLogOptions(container, &logs.Selection{LogFilePosition: logFilePosition}, usePreviousLogs)
+	rawLogs, err := readRawLogs(kubernetes, ns, podID, logOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedLines := logs.ToLogLines(rawLogs)
+	matches, limited := parsedLines.Search(query)
+	info := logs.LogInfo{
+		PodName:       podID,
+		ContainerName: container,
+		Truncated:     isReadLimitReached(int64(len(rawLogs)), int64(len(parsedLines)), logFilePosition),
+	}
+	if len(parsedLines) > 0 {
+		info.FromDate = parsedLines[0].Timestamp
+		info.ToDate = parsedLines[len(parsedLines)-1].Timestamp
+	}
+
+	return &logs.LogSearchResult{Info: info, Matches: matches, Limited: limited}, nil
+}
@@ -0,0 +1,129 @@
+package container
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/logs"
+)
+
+const (
+	// Lines of the existing logs sent before the new ones, when the client does not ask for other number.
+	DefaultFollowTailLines int64 = 100
+	// Longer lines end the stream, so that a single line can not exhaust memory of the backend.
+	maxFollowedLineSize = 1024 * 1024
+)
+
+type FollowOptions struct {
+	TailLines int64
+	// MaxLinesPerSecond limits lines sent to the client, 0 disables the limit.
+	MaxLinesPerSecond int
+}
+
+// FollowLogs sends lines of the container logs, including new ones, until the context is done, the container
+// terminates or send fails. Lines are read only after the previous one has been sent, so slow clients slow down
+// reading of the stream instead of piling lines up in memory.
+func FollowLogs(ctx context.Context, kubernetes kubernetes.Interface, ns, podID, container string,
+	options FollowOptions, send func(logs.LogLine) error) error {
+	if len(container) == 0 {
+		pod, err := kubernetes.CoreV1().Pods(ns).Get(ctx, podID, metaV1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		container = pod.Spec.Containers[0].Name
+	}
+
+	tailLines := options.TailLines
+	logStream, err := kubernetes.CoreV1().Pods(ns).GetLogs(podID, &v1.PodLogOptions{
+		Container:  container,
+		Follow:     true,
+		Timestamps: true,
+		TailLines:  &tailLines,
+	}).Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer logStream.Close()
+
+	log.Printf("Following logs of container %s in pod %s in namespace %s", container, podID, ns)
+	return sendLines(ctx, logStream, newLineLimiter(options.MaxLinesPerSecond), send)
+}
+
+func sendLines(ctx context.Context, reader io.Reader, limiter *lineLimiter, send func(logs.LogLine) error) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFollowedLineSize)
+	for scanner.Scan() {
+		if len(scanner.Text()) == 0 {
+			continue
+		}
+
+		if err := limiter.wait(ctx); err != nil {
+			return err
+		}
+
+		if err := send(logs.ToLogLine(scanner.Text())); err != nil {
+			return err
+		}
+	}
+
+	// Stream is closed when the context is done, which is not an error of the stream.
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return scanner.Err()
+}
+
+// lineLimiter allows at most max lines in every second.
+type lineLimiter struct {
+	max         int
+	count       int
+	windowStart time.Time
+	now         func() time.Time
+	sleep       func(ctx context.Context, duration time.Duration) error
+}
+
+func (self *lineLimiter) wait(ctx context.Context) error {
+	if self.max <= 0 {
+		return nil
+	}
+
+	if self.now().Sub(self.windowStart) >= time.Second {
+		self.windowStart = self.now()
+		self.count = 0
+	}
+
+	if self.count >= self.max {
+		if err := self.sleep(ctx, time.Second-self.now().Sub(self.windowStart)); err != nil {
+			return err
+		}
+
+		self.windowStart = self.now()
+		self.count = 0
+	}
+
+	self.count++
+	return nil
+}
+
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func newLineLimiter(max int) *lineLimiter {
+	return &lineLimiter{max: max, now: time.Now, sleep: sleep}
+}
@@ -0,0 +1,92 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/logs"
+)
+
+func TestSendLines(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected []logs.LogLine
+	}{
+		{"", []logs.LogLine{}},
+		{
+			"1 a\n\n2 b\n",
+			[]logs.LogLine{
+				{Timestamp: "1", Content: "a"},
+				{Timestamp: "2", Content: "b"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		sent := make([]logs.LogLine, 0)
+		err := sendLines(context.Background(), strings.NewReader(c.input), newLineLimiter(0),
+			func(line logs.LogLine) error {
+				sent = append(sent, line)
+				return nil
+			})
+		if err != nil {
+			t.Errorf("sendLines(%q): expected no error but got: %s", c.input, err)
+		}
+
+		if !reflect.DeepEqual(sent, c.expected) {
+			t.Errorf("sendLines(%q) == %#v, expected %#v", c.input, sent, c.expected)
+		}
+	}
+}
+
+func TestSendLinesStopsWhenSendFails(t *testing.T) {
+	expected := errors.New("connection closed")
+	calls := 0
+	err := sendLines(context.Background(), strings.NewReader("1 a\n2 b\n"), newLineLimiter(0),
+		func(line logs.LogLine) error {
+			calls++
+			return expected
+		})
+	if err != expected || calls != 1 {
+		t.Errorf("sendLines(): expected %s after 1 call but got %v after %d", expected, err, calls)
+	}
+}
+
+func TestLineLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	slept := make([]time.Duration, 0)
+	limiter := newLineLimiter(2)
+	limiter.now = func() time.Time { return now }
+	limiter.sleep = func(ctx context.Context, duration time.Duration) error {
+		slept = append(slept, duration)
+		now = now.Add(duration)
+		return nil
+	}
+
+	for i := 0; i < 5; i++ {
+		if err := limiter.wait(context.Background()); err != nil {
+			t.Fatalf("wait(): expected no error but got: %s", err)
+		}
+		now = now.Add(100 * time.Millisecond)
+	}
+
+	expected := []time.Duration{800 * time.Millisecond, 800 * time.Millisecond}
+	if !reflect.DeepEqual(slept, expected) {
+		t.Errorf("wait(): expected sleeps %v but got %v", expected, slept)
+	}
+}
+
+func TestLineLimiterCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	limiter := newLineLimiter(1)
+	limiter.count = 1
+	limiter.windowStart = time.Now()
+	if err := limiter.wait(ctx); err != context.Canceled {
+		t.Errorf("wait(): expected %s but got %v", context.Canceled, err)
+	}
+}
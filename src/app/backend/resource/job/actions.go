@@ -0,0 +1,93 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	batch "k8s.io/api/batch/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/common"
+)
+
+// Labels added to jobs and their pod templates by the job controller, in both the legacy and the prefixed form.
+// They bind pods to the original job, so they are not copied to the re-run job.
+var controllerLabels = []string{
+	"controller-uid",
+	"job-name",
+	"batch.kubernetes.io/controller-uid",
+	"batch.kubernetes.io/job-name",
+}
+
+const (
+	// RerunOfAnnotation holds name of the job the re-run job was created from.
+	RerunOfAnnotation = "kubeportal.io/rerun-of"
+	// Job names end up in pod labels, which can not be longer than 63 characters.
+	maxRerunPrefixLength = 50
+)
+
+// DeleteJob deletes the job together with its pods.
+func DeleteJob(client kubernetes.Interface, namespace, name string) error {
+	log.Printf("Deleting job %s in namespace %s", name, namespace)
+	propagationPolicy := metaV1.DeletePropagationBackground
+	return client.BatchV1().Jobs(namespace).Delete(context.TODO(), name,
+		metaV1.DeleteOptions{PropagationPolicy: &propagationPolicy})
+}
+
+// RerunJob creates a new job with the spec of the given finished job.
+func RerunJob(client kubernetes.Interface, namespace, name string) (*Job, error) {
+	original, err := client.BatchV1().Jobs(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if getJobStatus(original).Status == JobStatusRunning {
+		return nil, errors.NewBadRequest(fmt.Sprintf("job %s is still running, only finished jobs can be re-run",
+			name))
+	}
+
+	prefix := original.Name
+	if len(prefix) > maxRerunPrefixLength {
+		prefix = prefix[:maxRerunPrefixLength]
+	}
+
+	spec := *original.Spec.DeepCopy()
+	// Selector generated for the original job matches only its pods, the new one gets its own. Manual selector
+	// would match pods of the original job too, so it is replaced with the generated one as well.
+	spec.Selector = nil
+	spec.ManualSelector = nil
+	for _, label := range controllerLabels {
+		delete(spec.Template.Labels, label)
+	}
+
+	labels := map[string]string{}
+	for key, value := range original.Labels {
+		labels[key] = value
+	}
+	for _, label := range controllerLabels {
+		delete(labels, label)
+	}
+
+	jobToCreate := &batch.Job{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:        prefix + "-rerun-" + rand.String(5),
+			Namespace:   namespace,
+			Labels:      labels,
+			Annotations: map[string]string{RerunOfAnnotation: original.Name},
+		},
+		Spec: spec,
+	}
+
+	log.Printf("Re-running job %s in namespace %s as %s", name, namespace, jobToCreate.Name)
+	created, err := client.BatchV1().Jobs(namespace).Create(context.TODO(), jobToCreate, metaV1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := toJob(created, &common.PodInfo{})
+	return &result, nil
+}
@@ -0,0 +1,117 @@
+package job_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/resource/job"
+)
+
+const namespace = "default"
+
+func newJob(name string, condition batch.JobConditionType) *batch.Job {
+	controllerLabels := func() map[string]string {
+		return map[string]string{
+			"controller-uid":                     "uid",
+			"job-name":                           name,
+			"batch.kubernetes.io/controller-uid": "uid",
+			"batch.kubernetes.io/job-name":       name,
+		}
+	}
+	labels := controllerLabels()
+	labels["app"] = "backup"
+
+	result := &batch.Job{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: batch.JobSpec{
+			Selector: &metaV1.LabelSelector{
+				MatchLabels: map[string]string{"batch.kubernetes.io/controller-uid": "uid"},
+			},
+			Template: v1.PodTemplateSpec{ObjectMeta: metaV1.ObjectMeta{Labels: controllerLabels()}},
+		},
+	}
+
+	if len(condition) > 0 {
+		result.Status.Conditions = []batch.JobCondition{{Type: condition, Status: v1.ConditionTrue}}
+	}
+
+	return result
+}
+
+func TestRerunJob(t *testing.T) {
+	longName := strings.Repeat("backup", 10)
+	manual := newJob("manual", batch.JobComplete)
+	manualSelector := true
+	manual.Spec.ManualSelector = &manualSelector
+	manual.Spec.Selector = &metaV1.LabelSelector{MatchLabels: map[string]string{"app": "backup"}}
+	manual.Spec.Template.Labels["app"] = "backup"
+	client := fake.NewSimpleClientset(newJob("backup", batch.JobComplete), newJob(longName, batch.JobFailed),
+		newJob("running", ""), manual)
+
+	for _, name := range []string{"backup", longName, "manual"} {
+		result, err := job.RerunJob(client, namespace, name)
+		if err != nil {
+			t.Fatalf("RerunJob(%s): expected no error but got: %s", name, err)
+		}
+
+		created, err := client.BatchV1().Jobs(namespace).Get(context.TODO(), result.ObjectMeta.Name,
+			metaV1.GetOptions{})
+		if err != nil {
+			t.Fatalf("RerunJob(%s): expected job to be created but got: %s", name, err)
+		}
+
+		if len(created.Name) > 63 || created.Annotations[job.RerunOfAnnotation] != name {
+			t.Errorf("RerunJob(%s): expected job with valid name annotated with the original one but got %+v",
+				name, created.ObjectMeta)
+		}
+
+		// Manual selector of the original job would match its pods too.
+		if created.Spec.Selector != nil || created.Spec.ManualSelector != nil ||
+			hasControllerLabels(created.Spec.Template.Labels) || created.Labels["app"] != "backup" ||
+			len(created.Labels) != 1 {
+			t.Errorf("RerunJob(%s): expected labels of the job controller to be dropped but got %+v, %+v", name,
+				created.Labels, created.Spec)
+		}
+	}
+
+	if _, err := job.RerunJob(client, namespace, "running"); !errors.IsBadRequest(err) {
+		t.Errorf("RerunJob(running): expected running job not to be re-run but got %v", err)
+	}
+
+	if _, err := job.RerunJob(client, namespace, "unknown"); !errors.IsNotFound(err) {
+		t.Errorf("RerunJob(unknown): expected not found error but got %v", err)
+	}
+}
+
+func hasControllerLabels(labels map[string]string) bool {
+	for key := range labels {
+		if strings.Contains(key, "controller-uid") || strings.Contains(key, "job-name") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestDeleteJob(t *testing.T) {
+	client := fake.NewSimpleClientset(newJob("backup", batch.JobComplete))
+	if err := job.DeleteJob(client, namespace, "backup"); err != nil {
+		t.Fatalf("DeleteJob(): expected no error but got: %s", err)
+	}
+
+	if _, err := client.BatchV1().Jobs(namespace).Get(context.TODO(), "backup",
+		metaV1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("DeleteJob(): expected job to be deleted but got %v", err)
+	}
+}
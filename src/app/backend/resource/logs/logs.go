@@ -74,6 +74,13 @@ type LogLines []LogLine
 type LogLine struct {
 	Timestamp LogTimestamp `json:"timestamp"`
 	Content   string       `json:"content"`
+	// Pod and container are set only on lines of logs aggregated from multiple containers.
+	PodName       string `json:"podName,omitempty"`
+	ContainerName string `json:"containerName,omitempty"`
+	// Level, message and other fields are set only on JSON and logfmt lines, when structured parsing is asked for.
+	Level   string            `json:"level,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Fields  map[string]string `json:"fields,omitempty"`
 }
 
 type LogTimestamp string
@@ -166,16 +173,18 @@ func ToLogLines(rawLogs string) LogLines {
 	logLines := LogLines{}
 	for _, line := range strings.Split(rawLogs, "\n") {
 		if line != "" {
-			startsWithDate := ('0' <= line[0] && line[0] <= '9')
-			idx := strings.Index(line, " ")
-			if idx > 0 && startsWithDate {
-				timestamp := LogTimestamp(line[0:idx])
-				content := line[idx+1:]
-				logLines = append(logLines, LogLine{Timestamp: timestamp, Content: content})
-			} else {
-				logLines = append(logLines, LogLine{Timestamp: LogTimestamp("0"), Content: line})
-			}
+			logLines = append(logLines, ToLogLine(line))
 		}
 	}
 	return logLines
 }
+
+// ToLogLine parses a single non-empty line of logs read with timestamps.
+func ToLogLine(line string) LogLine {
+	startsWithDate := ('0' <= line[0] && line[0] <= '9')
+	idx := strings.Index(line, " ")
+	if idx > 0 && startsWithDate {
+		return LogLine{Timestamp: LogTimestamp(line[0:idx]), Content: line[idx+1:]}
+	}
+	return LogLine{Timestamp: LogTimestamp("0"), Content: line}
+}
@@ -0,0 +1,106 @@
+package logs
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+)
+
+const (
+	// Lines of context returned around each match, when the client does not ask for other number.
+	DefaultSearchContextLines = 3
+	MaxSearchContextLines     = 50
+	// Search stops after this number of matches, so that frequent patterns do not return the whole logs.
+	MaxSearchMatches = 1000
+)
+
+// SearchQuery selects lines of logs matching a substring or a regular expression.
+type SearchQuery struct {
+	matcher      func(string) bool
+	ContextLines int
+}
+
+type LogSearchResult struct {
+	Info    LogInfo    `json:"info"`
+	Matches []LogMatch `json:"matches"`
+	// Limited is set when the search stopped after MaxSearchMatches matches.
+	Limited bool `json:"limited"`
+}
+
+// LogMatch is a matching line with lines around it. Id can be used as the reference point of a selection to page
+// around the match.
+type LogMatch struct {
+	Id     LogLineId `json:"id"`
+	Line   LogLine   `json:"line"`
+	Before LogLines  `json:"before"`
+	After  LogLines  `json:"after"`
+}
+
+// Search returns lines matching the query, in the order of the logs.
+func (self LogLines) Search(query *SearchQuery) ([]LogMatch, bool) {
+	matches := make([]LogMatch, 0)
+	for i, line := range self {
+		if !query.matcher(line.Content) {
+			continue
+		}
+
+		if len(matches) == MaxSearchMatches {
+			return matches, true
+		}
+
+		before := i - query.ContextLines
+		if before < 0 {
+			before = 0
+		}
+		after := i + 1 + query.ContextLines
+		if after > len(self) {
+			after = len(self)
+		}
+
+		matches = append(matches, LogMatch{
+			Id:     *self.createLogLineId(i),
+			Line:   line,
+			Before: self[before:i],
+			After:  self[i+1 : after],
+		})
+	}
+	return matches, false
+}
+
+// NewSearchQuery returns the query matching lines containing the pattern, or matching it when regex is set.
+// Context lines are capped by MaxSearchContextLines.
+func NewSearchQuery(pattern string, regex, ignoreCase bool, contextLines int) (*SearchQuery, error) {
+	if len(pattern) == 0 {
+		return nil, errors.NewBadRequest("search pattern is empty")
+	}
+
+	if contextLines < 0 {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid number of context lines: %d", contextLines))
+	}
+	if contextLines > MaxSearchContextLines {
+		contextLines = MaxSearchContextLines
+	}
+
+	if !regex && !ignoreCase {
+		return &SearchQuery{
+			matcher:      func(content string) bool { return strings.Contains(content, pattern) },
+			ContextLines: contextLines,
+		}, nil
+	}
+
+	if !regex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid search pattern: %s", err))
+	}
+
+	return &SearchQuery{matcher: compiled.MatchString, ContextLines: contextLines}, nil
+}
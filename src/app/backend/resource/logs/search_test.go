@@ -0,0 +1,88 @@
+package logs
+
+import (
+	"reflect"
+	"testing"
+
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func TestSearch(t *testing.T) {
+	lines := LogLines{
+		{Timestamp: "1", Content: "starting"},
+		{Timestamp: "2", Content: "ERROR first"},
+		{Timestamp: "2", Content: "retrying"},
+		{Timestamp: "3", Content: "error second"},
+		{Timestamp: "4", Content: "done"},
+	}
+
+	cases := []struct {
+		pattern    string
+		regex      bool
+		ignoreCase bool
+		context    int
+		expected   []LogMatch
+	}{
+		{"missing", false, false, 1, []LogMatch{}},
+		{
+			"ERROR", false, false, 1,
+			[]LogMatch{{
+				Id:     LogLineId{LogTimestamp: "2", LineNum: -2},
+				Line:   lines[1],
+				Before: lines[0:1],
+				After:  lines[2:3],
+			}},
+		},
+		{
+			"error", false, true, 0,
+			[]LogMatch{
+				{Id: LogLineId{LogTimestamp: "2", LineNum: -2}, Line: lines[1], Before: lines[1:1], After: lines[2:2]},
+				{Id: LogLineId{LogTimestamp: "3", LineNum: -1}, Line: lines[3], Before: lines[3:3], After: lines[4:4]},
+			},
+		},
+		{
+			"^(start|done)", true, false, 2,
+			[]LogMatch{
+				{Id: LogLineId{LogTimestamp: "1", LineNum: -1}, Line: lines[0], Before: lines[0:0], After: lines[1:3]},
+				{Id: LogLineId{LogTimestamp: "4", LineNum: 1}, Line: lines[4], Before: lines[2:4], After: lines[5:5]},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		query, err := NewSearchQuery(c.pattern, c.regex, c.ignoreCase, c.context)
+		if err != nil {
+			t.Fatalf("NewSearchQuery(%s): expected no error but got: %s", c.pattern, err)
+		}
+
+		actual, limited := lines.Search(query)
+		if !reflect.DeepEqual(actual, c.expected) || limited {
+			t.Errorf("Search(%s) == %+v, expected %+v", c.pattern, actual, c.expected)
+		}
+
+		for _, match := range actual {
+			if index := lines.getLineIndex(&match.Id); !reflect.DeepEqual(lines[index], match.Line) {
+				t.Errorf("Search(%s): id %+v does not reference the matching line", c.pattern, match.Id)
+			}
+		}
+	}
+}
+
+func TestNewSearchQueryInvalid(t *testing.T) {
+	cases := []struct {
+		pattern string
+		regex   bool
+		context int
+	}{
+		{"", false, 0},
+		{"(", true, 0},
+		{"error", false, -1},
+	}
+
+	for _, c := range cases {
+		if _, err := NewSearchQuery(c.pattern, c.regex, false, c.context); !k8sErrors.IsBadRequest(err) {
+			t.Errorf("NewSearchQuery(%s, %t, %d): expected bad request error but got %v", c.pattern, c.regex,
+				c.context, err)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/donghoon-khan/kubeportal/src/app/backend/errors"
+)
+
+var levelKeys = []string{"level", "lvl", "severity"}
+
+var messageKeys = []string{"msg", "message"}
+
+// Levels with more spellings are normalized, so that filtering by one of them matches the others.
+var levelAliases = map[string]string{
+	"warning": "warn",
+	"err":     "error",
+	"crit":    "critical",
+	"dbg":     "debug",
+}
+
+// StructuredQuery enables parsing of JSON and logfmt lines and selects parsed lines by their level and fields. Empty
+// query only parses lines.
+type StructuredQuery struct {
+	Levels []string
+	Fields map[string]string
+}
+
+func (self *StructuredQuery) isFilter() bool {
+	return len(self.Levels) > 0 || len(self.Fields) > 0
+}
+
+func (self *StructuredQuery) matches(line *LogLine) bool {
+	if len(self.Levels) > 0 && !contains(self.Levels, line.Level) {
+		return false
+	}
+
+	for key, value := range self.Fields {
+		if fieldValue, exists := line.Fields[key]; !exists || fieldValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Structured parses JSON and logfmt lines and returns those matching the query. Lines that are neither JSON nor
+// logfmt are only returned when the query does not filter lines.
+func (self LogLines) Structured(query *StructuredQuery) LogLines {
+	result := make(LogLines, 0, len(self))
+	for _, line := range self {
+		parseStructured(&line)
+		if query.isFilter() && !query.matches(&line) {
+			continue
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
+func parseStructured(line *LogLine) {
+	fields, ok := parseJSON(line.Content)
+	if !ok {
+		fields, ok = parseLogfmt(line.Content)
+	}
+	if !ok {
+		return
+	}
+
+	line.Level = normalizeLevel(popField(fields, levelKeys))
+	line.Message = popField(fields, messageKeys)
+	line.Fields = fields
+}
+
+// parseJSON returns top level values of a JSON object. Values other than strings are returned as JSON.
+func parseJSON(content string) (map[string]string, bool) {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "{") {
+		return nil, false
+	}
+
+	object := make(map[string]json.RawMessage)
+	if err := json.Unmarshal([]byte(content), &object); err != nil {
+		return nil, false
+	}
+
+	fields := make(map[string]string, len(object))
+	for key, raw := range object {
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			value = string(raw)
+		}
+		fields[key] = value
+	}
+	return fields, true
+}
+
+// parseLogfmt returns key=value pairs of the line. Values can be quoted. Keys without values are not accepted, so
+// that plain text containing "=" is not taken for logfmt.
+func parseLogfmt(content string) (map[string]string, bool) {
+	fields := make(map[string]string)
+	for i := 0; i < len(content); {
+		if content[i] == ' ' || content[i] == '\t' {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(content) && content[i] > ' ' && content[i] != '=' && content[i] != '"' {
+			i++
+		}
+		key := content[start:i]
+		if len(key) == 0 || i == len(content) || content[i] != '=' {
+			return nil, false
+		}
+
+		i++
+		value, next, ok := readLogfmtValue(content, i)
+		if !ok {
+			return nil, false
+		}
+		fields[key] = value
+		i = next
+	}
+	return fields, len(fields) > 0
+}
+
+func readLogfmtValue(content string, i int) (string, int, bool) {
+	if i < len(content) && content[i] == '"' {
+		for end := i + 1; end < len(content); end++ {
+			if content[end] == '\\' {
+				end++
+				continue
+			}
+
+			if content[end] == '"' {
+				var value string
+				if err := json.Unmarshal([]byte(content[i:end+1]), &value); err != nil {
+					return "", 0, false
+				}
+				return value, end + 1, true
+			}
+		}
+		return "", 0, false
+	}
+
+	start := i
+	for i < len(content) && content[i] != ' ' && content[i] != '\t' {
+		i++
+	}
+	return content[start:i], i, true
+}
+
+func popField(fields map[string]string, keys []string) string {
+	for _, key := range keys {
+		if value, exists := fields[key]; exists {
+			delete(fields, key)
+			return value
+		}
+	}
+	return ""
+}
+
+func normalizeLevel(level string) string {
+	level = strings.ToLower(level)
+	if alias, exists := levelAliases[level]; exists {
+		return alias
+	}
+	return level
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// NewStructuredQuery returns the query selecting lines with one of the levels and all the fields. Fields are
+// given as key=value.
+func NewStructuredQuery(levels, fields []string) (*StructuredQuery, error) {
+	query := &StructuredQuery{Levels: make([]string, 0), Fields: make(map[string]string)}
+	for _, level := range levels {
+		if len(level) > 0 {
+			query.Levels = append(query.Levels, normalizeLevel(level))
+		}
+	}
+
+	for _, field := range fields {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			return nil, errors.NewBadRequest(fmt.Sprintf("invalid field filter %q, expected key=value", field))
+		}
+		query.Fields[parts[0]] = parts[1]
+	}
+	return query, nil
+}
@@ -0,0 +1,93 @@
+package logs
+
+import (
+	"reflect"
+	"testing"
+
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func TestParseStructured(t *testing.T) {
+	cases := []struct {
+		content  string
+		expected LogLine
+	}{
+		{"plain text", LogLine{Content: "plain text"}},
+		{"GET /index.html status=200", LogLine{Content: "GET /index.html status=200"}},
+		{`{"broken": `, LogLine{Content: `{"broken": `}},
+		{
+			`{"level":"WARNING","msg":"disk almost full","free":0.1,"node":{"name":"n1"}}`,
+			LogLine{
+				Content: `{"level":"WARNING","msg":"disk almost full","free":0.1,"node":{"name":"n1"}}`,
+				Level:   "warn",
+				Message: "disk almost full",
+				Fields:  map[string]string{"free": "0.1", "node": `{"name":"n1"}`},
+			},
+		},
+		{
+			`lvl=err message="connection \"db\" refused" retries=3 path=/api`,
+			LogLine{
+				Content: `lvl=err message="connection \"db\" refused" retries=3 path=/api`,
+				Level:   "error",
+				Message: `connection "db" refused`,
+				Fields:  map[string]string{"retries": "3", "path": "/api"},
+			},
+		},
+		{`msg="unterminated`, LogLine{Content: `msg="unterminated`}},
+	}
+
+	for _, c := range cases {
+		actual := LogLine{Content: c.content}
+		parseStructured(&actual)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("parseStructured(%s) == %+v, expected %+v", c.content, actual, c.expected)
+		}
+	}
+}
+
+func TestStructured(t *testing.T) {
+	lines := LogLines{
+		{Timestamp: "1", Content: "starting"},
+		{Timestamp: "2", Content: `level=info msg=ready port=8080`},
+		{Timestamp: "3", Content: `{"level":"error","msg":"request failed","path":"/api"}`},
+		{Timestamp: "4", Content: `level=error msg="request failed" path=/health`},
+	}
+
+	cases := []struct {
+		levels   []string
+		fields   []string
+		expected []LogTimestamp
+	}{
+		{nil, nil, []LogTimestamp{"1", "2", "3", "4"}},
+		{[]string{"ERR"}, nil, []LogTimestamp{"3", "4"}},
+		{[]string{"info", "error"}, []string{"path=/api"}, []LogTimestamp{"3"}},
+		{nil, []string{"port=8080"}, []LogTimestamp{"2"}},
+	}
+
+	for _, c := range cases {
+		query, err := NewStructuredQuery(c.levels, c.fields)
+		if err != nil {
+			t.Fatalf("NewStructuredQuery(%v, %v): expected no error but got: %s", c.levels, c.fields, err)
+		}
+
+		actual := make([]LogTimestamp, 0)
+		for _, line := range lines.Structured(query) {
+			actual = append(actual, line.Timestamp)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Structured(%v, %v) == %v, expected %v", c.levels, c.fields, actual, c.expected)
+		}
+	}
+
+	if lines[1].Fields != nil {
+		t.Errorf("Structured(): expected original lines not to be modified but got %+v", lines[1])
+	}
+}
+
+func TestNewStructuredQueryInvalidField(t *testing.T) {
+	for _, field := range []string{"path", "=/api"} {
+		if _, err := NewStructuredQuery(nil, []string{field}); !k8sErrors.IsBadRequest(err) {
+			t.Errorf("NewStructuredQuery(%s): expected bad request error but got %v", field, err)
+		}
+	}
+}